@@ -3,3 +3,10 @@
 # Examples:
 terraform import restapi_object.objects /api/objects
 terraform import restapi_object.object /api/objects/123
+
+# For objects identified by several fields, the identifier can instead be a JSON
+# object with the path, id, and optionally endpoint, read_path, id_attributes,
+# id_separator, response_envelope_key and read_patch (to store the object in the
+# shape the configuration uses).
+# The id is split into the id_attributes, which can be used as placeholders in read_path.
+terraform import restapi_object.record '{"path": "/zones/{zone}/records", "read_path": "/zones/{zone}/records/{name}", "id": "example.com:www", "id_attributes": ["zone", "name"]}'
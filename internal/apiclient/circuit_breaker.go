@@ -0,0 +1,154 @@
+package apiclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// ErrAPIUnavailable is returned (wrapped) by SendRequest when the circuit breaker is open
+// because the API has been failing consistently
+var ErrAPIUnavailable = errors.New("API unavailable")
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+func (s circuitState) String() string {
+	switch s {
+	case circuitOpen:
+		return "open"
+	case circuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// circuitBreaker stops sending requests to an API after too many consecutive connection
+// errors or 5xx responses, counting every attempt (retries included). Once the cooldown passes,
+// a single probe request is let through (half-open); its outcome decides whether the breaker
+// closes again or stays open.
+type circuitBreaker struct {
+	mux       sync.Mutex
+	threshold int64
+	cooldown  time.Duration
+	state     circuitState
+	failures  int64
+	openedAt  time.Time
+	probing   bool   // A half-open probe request is in flight
+	lastError string // The failure that tripped the breaker
+}
+
+// newCircuitBreaker builds the circuit breaker for a client. Returns nil if it is disabled.
+func newCircuitBreaker(ctx context.Context, opt *APIClientOpt) *circuitBreaker {
+	if opt.CircuitBreakerThreshold <= 0 {
+		return nil
+	}
+
+	cooldown := opt.CircuitBreakerCooldown
+	if cooldown <= 0 {
+		cooldown = 30
+	}
+
+	tflog.Info(ctx, "circuit breaker configured", map[string]interface{}{"threshold": opt.CircuitBreakerThreshold, "cooldown": cooldown})
+	return &circuitBreaker{
+		threshold: opt.CircuitBreakerThreshold,
+		cooldown:  time.Duration(cooldown) * time.Second,
+	}
+}
+
+// allow returns an error wrapping ErrAPIUnavailable if the request must not be sent
+func (cb *circuitBreaker) allow(ctx context.Context) error {
+	cb.mux.Lock()
+	defer cb.mux.Unlock()
+
+	switch cb.state {
+	case circuitOpen:
+		remaining := cb.cooldown - time.Since(cb.openedAt)
+		if remaining > 0 {
+			return fmt.Errorf("%w: circuit breaker opened after %d consecutive failures, the last being: %s; requests are rejected for another %s", ErrAPIUnavailable, cb.failures, cb.lastError, remaining.Round(time.Second))
+		}
+		tflog.Info(ctx, "Circuit breaker cooldown elapsed, sending probe request")
+		cb.state = circuitHalfOpen
+		cb.probing = true
+	case circuitHalfOpen:
+		if cb.probing {
+			return fmt.Errorf("%w: circuit breaker is waiting for a probe request to succeed after %d consecutive failures, the last being: %s", ErrAPIUnavailable, cb.failures, cb.lastError)
+		}
+		cb.probing = true
+	}
+	return nil
+}
+
+// record updates the breaker with the outcome of an attempt of a request that was allowed.
+// completed is false if the request ended without an attempt being seen through by the API;
+// failure is non-nil if the API should be considered failing.
+func (cb *circuitBreaker) record(ctx context.Context, completed bool, failure error) {
+	cb.mux.Lock()
+	defer cb.mux.Unlock()
+
+	if !completed {
+		// No verdict about the API - let another request probe it
+		cb.probing = false
+		return
+	}
+
+	if failure == nil {
+		if cb.state != circuitClosed {
+			tflog.Info(ctx, "Circuit breaker closed, API is responding again", map[string]interface{}{"previous_state": cb.state.String()})
+		}
+		cb.state = circuitClosed
+		cb.failures = 0
+		cb.probing = false
+		return
+	}
+
+	cb.failures++
+	cb.lastError = failure.Error()
+	if cb.state == circuitHalfOpen || cb.failures >= cb.threshold {
+		tflog.Warn(ctx, "Circuit breaker opened, failing requests fast", map[string]interface{}{"failures": cb.failures, "cooldown": cb.cooldown.String(), "error": cb.lastError})
+		cb.state = circuitOpen
+		cb.openedAt = time.Now()
+		cb.probing = false
+	}
+}
+
+// checkRetry wraps a retry policy so that the breaker records the outcome of every attempt.
+// Once the breaker opens, the request is not retried any further.
+func (cb *circuitBreaker) checkRetry(policy retryablehttp.CheckRetry) retryablehttp.CheckRetry {
+	return func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		// A cancelled attempt says nothing about the health of the API
+		if ctx.Err() != nil {
+			return policy(ctx, resp, err)
+		}
+
+		failure := err
+		if err == nil && resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented {
+			failure = fmt.Errorf("unexpected response code '%d'", resp.StatusCode)
+		}
+		cb.record(ctx, true, failure)
+
+		retry, checkErr := policy(ctx, resp, err)
+		if !retry {
+			return retry, checkErr
+		}
+
+		cb.mux.Lock()
+		defer cb.mux.Unlock()
+		if cb.state == circuitOpen {
+			return false, fmt.Errorf("%w: circuit breaker opened after %d consecutive failures, the last being: %s", ErrAPIUnavailable, cb.failures, cb.lastError)
+		}
+		return retry, checkErr
+	}
+}
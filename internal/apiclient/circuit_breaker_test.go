@@ -0,0 +1,154 @@
+package apiclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCircuitBreakerStates tests the transitions between closed, open and half-open
+func TestCircuitBreakerStates(t *testing.T) {
+	ctx := context.Background()
+
+	assert.Nil(t, newCircuitBreaker(ctx, &APIClientOpt{}), "No breaker when no threshold is configured")
+
+	cb := newCircuitBreaker(ctx, &APIClientOpt{CircuitBreakerThreshold: 2, CircuitBreakerCooldown: 1})
+	require.NotNil(t, cb)
+	failure := errors.New("connection refused")
+
+	// A success in between resets the count of consecutive failures
+	require.NoError(t, cb.allow(ctx))
+	cb.record(ctx, true, failure)
+	require.NoError(t, cb.allow(ctx))
+	cb.record(ctx, true, nil)
+	require.NoError(t, cb.allow(ctx))
+	cb.record(ctx, true, failure)
+	assert.Equal(t, circuitClosed, cb.state)
+
+	require.NoError(t, cb.allow(ctx))
+	cb.record(ctx, true, failure)
+	assert.Equal(t, circuitOpen, cb.state)
+
+	err := cb.allow(ctx)
+	assert.ErrorIs(t, err, ErrAPIUnavailable)
+	assert.Contains(t, err.Error(), "connection refused")
+
+	// After the cooldown only a single probe is let through
+	cb.openedAt = time.Now().Add(-2 * time.Second)
+	require.NoError(t, cb.allow(ctx))
+	assert.Equal(t, circuitHalfOpen, cb.state)
+	assert.ErrorIs(t, cb.allow(ctx), ErrAPIUnavailable)
+
+	// An abandoned probe lets another request probe
+	cb.record(ctx, false, nil)
+	require.NoError(t, cb.allow(ctx))
+
+	// A failed probe opens the breaker again straight away
+	cb.record(ctx, true, failure)
+	assert.Equal(t, circuitOpen, cb.state)
+
+	cb.openedAt = time.Now().Add(-2 * time.Second)
+	require.NoError(t, cb.allow(ctx))
+	cb.record(ctx, true, nil)
+	assert.Equal(t, circuitClosed, cb.state)
+	assert.NoError(t, cb.allow(ctx))
+}
+
+// TestSendRequestCircuitBreaker tests that requests fail fast once the API keeps returning 5xx
+func TestSendRequestCircuitBreaker(t *testing.T) {
+	var hits, healthy int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		if atomic.LoadInt32(&healthy) == 1 {
+			w.Write([]byte(`{}`))
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, err := NewAPIClient(&APIClientOpt{
+		URI:                     server.URL,
+		Timeout:                 2,
+		CircuitBreakerThreshold: 3,
+		CircuitBreakerCooldown:  60,
+	})
+	require.NoError(t, err)
+
+	for range 2 {
+		_, _, err := client.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrAPIUnavailable)
+	}
+
+	// The request that trips the breaker reports it
+	_, _, err = client.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+	assert.ErrorIs(t, err, ErrAPIUnavailable)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&hits))
+
+	_, _, err = client.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+	assert.ErrorIs(t, err, ErrAPIUnavailable)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&hits), "Open breaker should not send requests")
+
+	// Once the cooldown passes, a successful probe closes the breaker
+	atomic.StoreInt32(&healthy, 1)
+	client.circuitBreaker.openedAt = time.Now().Add(-time.Minute)
+	_, _, err = client.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+	require.NoError(t, err)
+	assert.Equal(t, circuitClosed, client.circuitBreaker.state)
+}
+
+// TestSendRequestCircuitBreakerCountsRetries tests that each retry counts towards the threshold
+func TestSendRequestCircuitBreakerCountsRetries(t *testing.T) {
+	var hits int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, err := NewAPIClient(&APIClientOpt{
+		URI:                     server.URL,
+		Timeout:                 2,
+		RetryMax:                5,
+		CircuitBreakerThreshold: 2,
+		CircuitBreakerCooldown:  60,
+	})
+	require.NoError(t, err)
+
+	_, _, err = client.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+	assert.ErrorIs(t, err, ErrAPIUnavailable)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&hits), "Retries should stop once the breaker opens")
+	assert.Equal(t, circuitOpen, client.circuitBreaker.state)
+}
+
+// TestSendRequestCircuitBreakerIgnoresClientErrors tests that 4xx responses do not trip the breaker
+func TestSendRequestCircuitBreakerIgnoresClientErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := NewAPIClient(&APIClientOpt{
+		URI:                     server.URL,
+		Timeout:                 2,
+		CircuitBreakerThreshold: 1,
+	})
+	require.NoError(t, err)
+
+	for range 3 {
+		_, status, err := client.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+		require.Error(t, err)
+		assert.Equal(t, http.StatusNotFound, status)
+	}
+	assert.Equal(t, circuitClosed, client.circuitBreaker.state)
+}
@@ -8,7 +8,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"math"
 	"net/http"
 	"net/http/cookiejar"
 	"net/http/httputil"
@@ -20,57 +19,89 @@ import (
 	"github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
-	"golang.org/x/time/rate"
 )
 
 type APIClientOpt struct {
-	URI                 string
-	Insecure            bool
-	Username            string
-	Password            string
-	Headers             map[string]string
-	Timeout             int64 // Timeout in seconds for HTTP requests
-	IDAttribute         string
-	CreateMethod        string
-	ReadMethod          string
-	ReadData            string
-	UpdateMethod        string
-	UpdateData          string
-	DestroyMethod       string
-	DestroyData         string
-	CopyKeys            []string
-	WriteReturnsObject  bool
-	CreateReturnsObject bool
-	XSSIPrefix          string
-	UseCookies          bool
-	RateLimit           float64 // RateLimit in requests per second (0 = unlimited)
-	OAuthClientID       string
-	OAuthClientSecret   string
-	OAuthScopes         []string
-	OAuthTokenURL       string
-	OAuthEndpointParams url.Values
-	CertFile            string
-	KeyFile             string
-	RootCAFile          string
-	CertString          string
-	KeyString           string
-	RootCAString        string
-	Debug               bool
-	RetryMax            int64
-	RetryWaitMin        int64
-	RetryWaitMax        int64
+	URI                     string              // Base URI
+	FailoverURIs            []string            // Base URIs to fail over to, in order, when URI can't be used
+	Endpoints               map[string]Endpoint // Additional named base URIs requests can be sent to
+	Insecure                bool
+	Username                string
+	Password                string
+	PasswordFile            string // File to read the password from, re-read when it changes
+	BearerTokenFile         string // File to read a bearer token from, re-read when it changes
+	Headers                 map[string]string
+	HeaderFiles             map[string]string // Header names and the files to read their values from, re-read when they change
+	Timeout                 int64             // Timeout in seconds for HTTP requests
+	IDAttribute             string
+	CreateMethod            string
+	ReadMethod              string
+	ReadData                string
+	UpdateMethod            string
+	UpdateData              string
+	DestroyMethod           string
+	DestroyData             string
+	CopyKeys                []string
+	PathVariables           map[string]string // Values for path placeholders not found in an object's data
+	WriteReturnsObject      bool
+	CreateReturnsObject     bool
+	ResponseEnvelopeKey     string // Path of objects in responses to create, read and update requests
+	RequestEnvelopeKey      string // Path objects are nested under in create and update requests
+	XSSIPrefix              string
+	UseCookies              bool
+	CookieFile              string      // File to keep cookies in between runs (implies UseCookies)
+	CSRF                    *CSRFConfig // How to obtain the CSRF token sent with unsafe requests
+	RateLimit               float64     // RateLimit in requests per second (0 = unlimited)
+	RateLimitBudgets        []RateLimitBudget
+	AdaptiveRateLimit       bool   // Adjust the rate limit according to rate limit headers returned by the server
+	MaxConcurrentRequests   int64  // Maximum requests in flight at once (0 = unlimited)
+	MaxConcurrentReads      int64  // Maximum GET/HEAD/OPTIONS requests in flight at once (0 = unlimited)
+	MaxConcurrentWrites     int64  // Maximum requests with any other method in flight at once (0 = unlimited)
+	CircuitBreakerThreshold int64  // Consecutive failures before requests fail fast (0 = disabled)
+	CircuitBreakerCooldown  int64  // Seconds to fail fast before probing the API again
+	RequestIDHeader         string // Header to send the per-request correlation ID in (defaults to X-Request-ID)
+	UserAgent               string
+	TracerProvider          trace.TracerProvider // OpenTelemetry tracer provider (defaults to the global provider)
+	MeterProvider           metric.MeterProvider // OpenTelemetry meter provider (defaults to the global provider)
+	CredentialProcess       *CredentialProcess   // Command that provides the credentials to send with each request
+	OAuthClientID           string
+	OAuthClientSecret       string
+	OAuthScopes             []string
+	OAuthTokenURL           string
+	OAuthEndpointParams     url.Values
+	CertFile                string
+	KeyFile                 string
+	RootCAFile              string
+	CertString              string
+	KeyString               string
+	RootCAString            string
+	Debug                   bool
+	RetryMax                int64
+	RetryWaitMin            int64
+	RetryWaitMax            int64
+	FailoverStatusCodes     []int // Response status codes that cause a request to be sent to the next URI
 }
 
 // APIClient is a HTTP client with additional controlling fields
 type APIClient struct {
 	httpClient          *retryablehttp.Client
 	uri                 string
+	endpoints           map[string]Endpoint
+	failover            map[string]*failoverGroup // Base URIs of the client ("") and each endpoint
+	failoverStatusCodes []int
 	insecure            bool
 	username            string
 	password            string
+	passwordFile        *secretFile
+	bearerTokenFile     *secretFile
 	headers             map[string]string
+	headerFiles         map[string]*secretFile
 	idAttribute         string
 	createMethod        string
 	readMethod          string
@@ -80,15 +111,68 @@ type APIClient struct {
 	destroyMethod       string
 	destroyData         string
 	copyKeys            []string
+	pathVariables       map[string]string
 	writeReturnsObject  bool
 	createReturnsObject bool
+	responseEnvelopeKey string
+	requestEnvelopeKey  string
 	xssiPrefix          string
-	rateLimiter         *rate.Limiter
+	rateLimiter         *rateLimiter
+	concurrency         *concurrencyLimiter
+	circuitBreaker      *circuitBreaker
+	telemetry           *telemetry
+	requestIDHeader     string
+	userAgent           string
 	debug               bool
 	oauthConfig         *clientcredentials.Config
+	credentialProcess   *credentialProcess
+	cookieJar           http.CookieJar
+	csrf                *csrfTokens
 	Opts                APIClientOpt
 }
 
+// Endpoint is a named base URI (with any base URIs to fail over to) on the same API client.
+// Requests sent to an endpoint share the client's transport, authentication and rate limiting,
+// with Headers set over the client's headers.
+type Endpoint struct {
+	URI          string
+	FailoverURIs []string
+	Headers      map[string]string
+}
+
+// RequestInterruptedError is returned by SendRequest when a request is abandoned because
+// its context was cancelled (e.g. Ctrl-C) or its deadline (e.g. a Terraform timeout) passed
+type RequestInterruptedError struct {
+	Method string
+	Path   string
+	Err    error
+}
+
+func (e *RequestInterruptedError) Error() string {
+	return fmt.Sprintf("%s %s interrupted: %s", e.Method, e.Path, e.Err)
+}
+
+func (e *RequestInterruptedError) Unwrap() error {
+	return e.Err
+}
+
+// interrupted wraps err in a RequestInterruptedError if the context has ended. The context is
+// checked rather than err itself because a per-request HTTP timeout also reports a deadline error.
+func interrupted(ctx context.Context, method string, path string, err error) error {
+	if ctx.Err() != nil {
+		return &RequestInterruptedError{Method: method, Path: path, Err: err}
+	}
+	return err
+}
+
+type contentTypeKey struct{}
+
+// withContentType sets the Content-Type of a request's body (such as a JSON patch document),
+// taking precedence over the default and the configured headers
+func withContentType(ctx context.Context, contentType string) context.Context {
+	return context.WithValue(ctx, contentTypeKey{}, contentType)
+}
+
 // NewAPIClient makes a new api client for RESTful calls
 func NewAPIClient(opt *APIClientOpt) (*APIClient, error) {
 	ctx := context.Background()
@@ -107,6 +191,14 @@ func NewAPIClient(opt *APIClientOpt) (*APIClient, error) {
 	// to this URL with our own root-prefixed location
 	opt.URI = strings.TrimSuffix(opt.URI, "/")
 
+	failover := map[string]*failoverGroup{"": newFailoverGroup(opt.URI, opt.FailoverURIs)}
+	for name, endpoint := range opt.Endpoints {
+		if endpoint.URI == "" {
+			return nil, fmt.Errorf("uri must be set for endpoint '%s'", name)
+		}
+		failover[name] = newFailoverGroup(endpoint.URI, endpoint.FailoverURIs)
+	}
+
 	if opt.CreateMethod == "" {
 		opt.CreateMethod = "POST"
 	}
@@ -171,20 +263,18 @@ func NewAPIClient(opt *APIClientOpt) (*APIClient, error) {
 
 	var cookieJar http.CookieJar
 
-	if opt.UseCookies {
+	if opt.CookieFile != "" {
+		jar, err := newPersistentJar(ctx, opt.CookieFile)
+		if err != nil {
+			return nil, err
+		}
+		cookieJar = jar
+	} else if opt.UseCookies || (opt.CSRF != nil && opt.CSRF.CookieName != "") {
+		// A CSRF token read from a cookie needs somewhere to keep the cookie
 		cookieJar, _ = cookiejar.New(nil)
 	}
 
-	var rateLimiter *rate.Limiter
-	if opt.RateLimit > 0 {
-		rateLimit := rate.Limit(opt.RateLimit)
-		// Bucket size determines burst capacity - at minimum 1 request, otherwise rounded rate
-		bucketSize := int(math.Max(math.Round(opt.RateLimit), 1))
-		tflog.Info(ctx, "rate limit configured", map[string]interface{}{"rateLimit": opt.RateLimit, "bucketSize": bucketSize})
-		rateLimiter = rate.NewLimiter(rateLimit, bucketSize)
-	} else {
-		tflog.Info(ctx, "rate limiting disabled", nil)
-	}
+	rateLimiter := newRateLimiter(ctx, opt)
 
 	tmpClient := cleanhttp.DefaultClient()
 	tmpClient.Timeout = time.Second * time.Duration(opt.Timeout)
@@ -212,14 +302,31 @@ func NewAPIClient(opt *APIClientOpt) (*APIClient, error) {
 	retryClient.RetryWaitMin = time.Duration(retryWaitMin) * time.Second
 	retryClient.RetryWaitMax = time.Duration(retryWaitMax) * time.Second
 	retryClient.HTTPClient = tmpClient
+	retryClient.RequestLogHook = recordRetry
+	retryClient.CheckRetry = failoverRetryPolicy
+
+	circuitBreaker := newCircuitBreaker(ctx, opt)
+	if circuitBreaker != nil {
+		retryClient.CheckRetry = circuitBreaker.checkRetry(failoverRetryPolicy)
+	}
 
 	client := APIClient{
 		httpClient:          retryClient,
 		rateLimiter:         rateLimiter,
+		concurrency:         newConcurrencyLimiter(ctx, opt),
+		circuitBreaker:      circuitBreaker,
+		telemetry:           newTelemetry(opt),
+		requestIDHeader:     opt.RequestIDHeader,
+		userAgent:           opt.UserAgent,
 		uri:                 opt.URI,
+		endpoints:           opt.Endpoints,
+		failover:            failover,
+		failoverStatusCodes: opt.FailoverStatusCodes,
 		insecure:            opt.Insecure,
 		username:            opt.Username,
 		password:            opt.Password,
+		passwordFile:        newSecretFile(opt.PasswordFile),
+		bearerTokenFile:     newSecretFile(opt.BearerTokenFile),
 		headers:             opt.Headers,
 		idAttribute:         opt.IDAttribute,
 		createMethod:        opt.CreateMethod,
@@ -230,10 +337,16 @@ func NewAPIClient(opt *APIClientOpt) (*APIClient, error) {
 		destroyMethod:       opt.DestroyMethod,
 		destroyData:         opt.DestroyData,
 		copyKeys:            opt.CopyKeys,
+		pathVariables:       opt.PathVariables,
 		writeReturnsObject:  opt.WriteReturnsObject,
 		createReturnsObject: opt.CreateReturnsObject,
+		responseEnvelopeKey: opt.ResponseEnvelopeKey,
+		requestEnvelopeKey:  opt.RequestEnvelopeKey,
 		xssiPrefix:          opt.XSSIPrefix,
 		debug:               opt.Debug,
+		credentialProcess:   newCredentialProcess(opt),
+		cookieJar:           cookieJar,
+		csrf:                newCSRFTokens(opt),
 		Opts:                *opt,
 	}
 
@@ -247,7 +360,22 @@ func NewAPIClient(opt *APIClientOpt) (*APIClient, error) {
 		}
 	}
 
+	// Read secret files up front so a missing file is reported when the client is set up
+	if len(opt.HeaderFiles) > 0 {
+		client.headerFiles = make(map[string]*secretFile, len(opt.HeaderFiles))
+		for name, path := range opt.HeaderFiles {
+			client.headerFiles[name] = newSecretFile(path)
+		}
+	}
+	for _, f := range client.secretFiles() {
+		if _, err := f.read(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	tflog.Debug(ctx, "Constructed client", map[string]interface{}{"details": client.String()})
+	retryClient.ErrorHandler = client.retriesExhausted
+
 	return &client, nil
 }
 
@@ -256,12 +384,21 @@ func NewAPIClient(opt *APIClientOpt) (*APIClient, error) {
 func (client *APIClient) String() string {
 	var buffer bytes.Buffer
 	buffer.WriteString(fmt.Sprintf("uri: %s\n", client.uri))
+	for name, endpoint := range client.endpoints {
+		buffer.WriteString(fmt.Sprintf("endpoint %s: %s\n", name, endpoint.URI))
+	}
+	buffer.WriteString(fmt.Sprintf("failover_status_codes: %v\n", client.failoverStatusCodes))
 	buffer.WriteString(fmt.Sprintf("insecure: %t\n", client.insecure))
 	buffer.WriteString(fmt.Sprintf("username: %s\n", client.username))
 	buffer.WriteString(fmt.Sprintf("password: %s\n", client.password))
+	for _, f := range client.secretFiles() {
+		buffer.WriteString(fmt.Sprintf("secret file: %s\n", f.path))
+	}
 	buffer.WriteString(fmt.Sprintf("id_attribute: %s\n", client.idAttribute))
 	buffer.WriteString(fmt.Sprintf("write_returns_object: %t\n", client.writeReturnsObject))
 	buffer.WriteString(fmt.Sprintf("create_returns_object: %t\n", client.createReturnsObject))
+	buffer.WriteString(fmt.Sprintf("response_envelope_key: %s\n", client.responseEnvelopeKey))
+	buffer.WriteString(fmt.Sprintf("request_envelope_key: %s\n", client.requestEnvelopeKey))
 	buffer.WriteString("headers:\n")
 	for k, v := range client.headers {
 		buffer.WriteString(fmt.Sprintf("  %s: %s\n", k, v))
@@ -270,23 +407,131 @@ func (client *APIClient) String() string {
 	for _, n := range client.copyKeys {
 		buffer.WriteString(fmt.Sprintf("  %s", n))
 	}
+	buffer.WriteString("path_variables:\n")
+	for k, v := range client.pathVariables {
+		buffer.WriteString(fmt.Sprintf("  %s: %s\n", k, v))
+	}
 	return buffer.String()
 }
 
+// secretFiles returns all the files the client reads secrets from
+func (client *APIClient) secretFiles() []*secretFile {
+	var files []*secretFile
+	for _, f := range []*secretFile{client.passwordFile, client.bearerTokenFile} {
+		if f != nil {
+			files = append(files, f)
+		}
+	}
+	for _, f := range client.headerFiles {
+		files = append(files, f)
+	}
+	return files
+}
+
+func (client *APIClient) requestIDHeaderName() string {
+	if client.requestIDHeader != "" {
+		return client.requestIDHeader
+	}
+	return DefaultRequestIDHeader
+}
+
+// retriesExhausted is the retry client's error handler. It returns the same error as the retry
+// client would by default, but keeps the request IDs of the last attempt.
+func (client *APIClient) retriesExhausted(resp *http.Response, err error, attempts int) (*http.Response, error) {
+	if resp == nil {
+		return nil, fmt.Errorf("giving up after %d attempt(s): %w", attempts, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	giveUp := fmt.Errorf("%s %s giving up after %d attempt(s)", resp.Request.Method, resp.Request.URL.Redacted(), attempts)
+	if err != nil {
+		giveUp = fmt.Errorf("%w: %w", giveUp, err)
+	}
+
+	return nil, &RequestError{
+		Method:          resp.Request.Method,
+		StatusCode:      resp.StatusCode,
+		RequestID:       resp.Request.Header.Get(client.requestIDHeaderName()),
+		ServerRequestID: resp.Header.Get(client.requestIDHeaderName()),
+		Err:             giveUp,
+		header:          resp.Header,
+	}
+}
+
+// HasEndpoint reports whether a named endpoint is configured on the client
+func (client *APIClient) HasEndpoint(name string) bool {
+	_, ok := client.endpoints[name]
+	return ok
+}
+
 // SendRequest is a helper function that handles sending/receiving and handling of HTTP data in and out.
 func (client *APIClient) SendRequest(ctx context.Context, method string, path string, data string, forceDebug bool) (string, int, error) {
-	fullURI := client.uri + path
+	return client.SendEndpointRequest(ctx, "", method, path, data, forceDebug)
+}
+
+// SendEndpointRequest is SendRequest against a named endpoint's base URI rather than the
+// client's. An empty endpoint name uses the client's own URI.
+func (client *APIClient) SendEndpointRequest(ctx context.Context, endpoint string, method string, path string, data string, forceDebug bool) (string, int, error) {
+	requestID := newRequestID()
+	ctx, span := client.telemetry.startRequestSpan(ctx, method, path)
+	span.SetAttributes(attribute.String("restapi.request_id", requestID))
+	if endpoint != "" {
+		span.SetAttributes(attribute.String("restapi.endpoint", endpoint))
+	}
+	start := time.Now()
+
+	body, status, err := client.sendRequest(ctx, requestID, endpoint, method, path, data, forceDebug)
+
+	// The CSRF token may have expired or been rotated along with the session. Get a fresh one and try once more.
+	var requestErr *RequestError
+	if errors.As(err, &requestErr) && requestErr.csrfRejected {
+		tflog.Info(ctx, "Request forbidden, refreshing the CSRF token and trying again", map[string]interface{}{"method": method, "path": path, "request_id": requestID})
+		client.csrf.invalidate(endpoint)
+		requestID = newRequestID()
+		span.SetAttributes(attribute.String("restapi.request_id", requestID))
+		body, status, err = client.sendRequest(ctx, requestID, endpoint, method, path, data, forceDebug)
+	}
+
+	// Make sure every failure carries the correlation ID. A RequestError of another request, such as
+	// the CSRF token fetch, is wrapped rather than reported as this one.
+	if errors.As(err, &requestErr) && requestErr.RequestID == requestID {
+		// The retry client's error handler does not know the path
+		if requestErr.Path == "" {
+			requestErr.Path = path
+		}
+	} else if err != nil {
+		err = &RequestError{Method: method, Path: path, StatusCode: status, RequestID: requestID, Err: err}
+	}
+
+	client.telemetry.endRequestSpan(ctx, span, method, path, status, err, time.Since(start))
+	return body, status, err
+}
+
+func (client *APIClient) sendRequest(ctx context.Context, requestID string, endpointName string, method string, path string, data string, forceDebug bool) (string, int, error) {
+	var endpointHeaders map[string]string
+	if endpointName != "" {
+		endpoint, ok := client.endpoints[endpointName]
+		if !ok {
+			return "", 0, fmt.Errorf("endpoint '%s' is not configured on the provider", endpointName)
+		}
+		endpointHeaders = endpoint.Headers
+	}
+
+	baseURIs := client.failover[endpointName]
+	uriIndex := baseURIs.activeIndex()
+	fullURI := baseURIs.uris[uriIndex] + path
 	var req *retryablehttp.Request
 	var err error
 
-	tflog.Debug(ctx, "Sending request", map[string]interface{}{"method": method, "path": path, "fullURI": fullURI, "data": data})
+	tflog.Debug(ctx, "Sending request", map[string]interface{}{"method": method, "path": path, "fullURI": fullURI, "data": data, "request_id": requestID, "endpoint": endpointName})
 
 	buffer := bytes.NewBuffer([]byte(data))
 
 	if data == "" {
-		req, err = retryablehttp.NewRequest(method, fullURI, nil)
+		req, err = retryablehttp.NewRequestWithContext(ctx, method, fullURI, nil)
 	} else {
-		req, err = retryablehttp.NewRequest(method, fullURI, buffer)
+		req, err = retryablehttp.NewRequestWithContext(ctx, method, fullURI, buffer)
 
 		// Default of application/json, but allow headers array to overwrite later
 		if err == nil {
@@ -297,28 +542,87 @@ func (client *APIClient) SendRequest(ctx context.Context, method string, path st
 		return "", 0, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 
+	if client.userAgent != "" {
+		req.Header.Set("User-Agent", client.userAgent)
+	}
+	req.Header.Set(client.requestIDHeaderName(), requestID)
+
 	// Allow for tokens or other pre-created secrets
 	if len(client.headers) > 0 {
 		for n, v := range client.headers {
 			req.Header.Set(n, v)
 		}
 	}
+	for n, v := range endpointHeaders {
+		req.Header.Set(n, v)
+	}
+	if contentType, ok := ctx.Value(contentTypeKey{}).(string); ok && contentType != "" && data != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	// Secrets kept in files are checked on every request so rotated values are picked up
+	for n, f := range client.headerFiles {
+		v, err := f.read(ctx)
+		if err != nil {
+			return "", 0, err
+		}
+		req.Header.Set(n, v)
+	}
+	if client.bearerTokenFile != nil {
+		token, err := client.bearerTokenFile.read(ctx)
+		if err != nil {
+			return "", 0, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	if client.credentialProcess != nil {
+		credentialHeaders, err := client.credentialProcess.headers(ctx)
+		if err != nil {
+			return "", 0, interrupted(ctx, method, path, err)
+		}
+		for n, v := range credentialHeaders {
+			req.Header.Set(n, v)
+		}
+	}
+
+	csrfSent := false
+	if client.csrf != nil && !isSafeMethod(method) {
+		token, err := client.csrf.token(ctx, client, endpointName, req.URL)
+		if err != nil {
+			return "", 0, interrupted(ctx, method, path, err)
+		}
+		if token != "" {
+			req.Header.Set(client.csrf.HeaderName, token)
+			csrfSent = true
+		}
+	}
 
 	if client.oauthConfig != nil {
-		// Embed our configured HTTP client (with certs, proxy, etc.) into the OAuth token request context
-		ctx := context.WithValue(context.Background(), oauth2.HTTPClient, client.httpClient)
-		tokenSource := client.oauthConfig.TokenSource(ctx)
+		// Embed our configured HTTP client (with certs, proxy, etc.) into the OAuth token request context.
+		// The token request is bound to the caller's context so it is aborted along with the operation.
+		tokenCtx := context.WithValue(ctx, oauth2.HTTPClient, client.httpClient.StandardClient())
+		tokenSource := client.oauthConfig.TokenSource(tokenCtx)
 		token, err := tokenSource.Token()
 		if err != nil {
-			return "", 0, err
+			return "", 0, interrupted(ctx, method, path, fmt.Errorf("failed to fetch OAuth token: %w", err))
 		}
 		req.Header.Set("Authorization", "Bearer "+token.AccessToken)
 	}
 
-	if client.username != "" && client.password != "" {
+	// Propagate the trace context (W3C traceparent) so the API's spans join the trace
+	client.telemetry.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	password := client.password
+	if client.passwordFile != nil {
+		if password, err = client.passwordFile.read(ctx); err != nil {
+			return "", 0, err
+		}
+	}
+	if client.username != "" && password != "" {
 		// Basic auth is applied after OAuth (if configured). If both are set, OAuth takes precedence
 		// as it was set on the Authorization header above
-		req.SetBasicAuth(client.username, client.password)
+		req.SetBasicAuth(client.username, password)
 	}
 
 	if client.debug || forceDebug {
@@ -328,14 +632,83 @@ func (client *APIClient) SendRequest(ctx context.Context, method string, path st
 		}
 	}
 
+	// Fail fast if the API has been failing consistently. The breaker sees the outcome of each
+	// attempt through the retry policy; if none was seen through, let another request probe the API.
+	if client.circuitBreaker != nil {
+		if err := client.circuitBreaker.allow(ctx); err != nil {
+			return "", 0, err
+		}
+		defer client.circuitBreaker.record(ctx, false, nil)
+	}
+
+	if client.concurrency != nil {
+		tflog.Debug(ctx, "Waiting for a request slot", map[string]interface{}{"method": method})
+		release, err := client.concurrency.acquire(ctx, method)
+		if err != nil {
+			return "", 0, &RequestInterruptedError{Method: method, Path: path, Err: fmt.Errorf("aborted while waiting for a request slot: %w", err)}
+		}
+		defer release()
+	}
+
 	if client.rateLimiter != nil {
 		tflog.Debug(ctx, "Waiting for rate limit availability")
-		_ = client.rateLimiter.Wait(ctx)
+		waitStart := time.Now()
+		err := client.rateLimiter.Wait(ctx, method, path)
+		trace.SpanFromContext(ctx).SetAttributes(attribute.Int64("restapi.rate_limit.wait_ms", time.Since(waitStart).Milliseconds()))
+		if err != nil {
+			return "", 0, &RequestInterruptedError{Method: method, Path: path, Err: fmt.Errorf("aborted while waiting for rate limit: %w", err)}
+		}
 	}
 
-	resp, err := client.httpClient.Do(req)
+	// Send the request to the active base URI, moving on to the next ones in turn if it can't be reached
+	resp, err := client.httpClient.Do(req.WithContext(withFailoverPending(ctx, len(baseURIs.uris) > 1)))
+	for attempt := 1; attempt < len(baseURIs.uris) && client.shouldFailOver(ctx, resp, err); attempt++ {
+		failed := baseURIs.uris[uriIndex]
+		uriIndex = (uriIndex + 1) % len(baseURIs.uris)
+		tflog.Warn(ctx, "Request failed, trying the next URI", map[string]interface{}{"method": method, "path": path, "failed_uri": failed, "next_uri": baseURIs.uris[uriIndex], "error": err, "status": responseStatus(resp)})
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+		}
+		if err := setRequestURI(req, baseURIs.uris[uriIndex]+path); err != nil {
+			return "", 0, fmt.Errorf("failed to create HTTP request: %w", err)
+		}
+		resp, err = client.httpClient.Do(req.WithContext(withFailoverPending(ctx, attempt < len(baseURIs.uris)-1)))
+	}
+	if !client.shouldFailOver(ctx, resp, err) {
+		baseURIs.setActive(ctx, uriIndex)
+	}
+
+	// Credentials from a credential process may have been revoked before their expiration.
+	// Get fresh ones and try once more.
+	if err == nil && resp.StatusCode == http.StatusUnauthorized && client.credentialProcess != nil {
+		tflog.Info(ctx, "Request unauthorized, running the credential process again", map[string]interface{}{"method": method, "path": path})
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+
+		client.credentialProcess.invalidate()
+		credentialHeaders, credErr := client.credentialProcess.headers(ctx)
+		if credErr != nil {
+			return "", 0, interrupted(ctx, method, path, credErr)
+		}
+		for n, v := range credentialHeaders {
+			req.Header.Set(n, v)
+		}
+		resp, err = client.httpClient.Do(req)
+	}
 	if err != nil {
-		return "", 0, err
+		// Retries that ran out on a 429 leave the quota the limiter needs most on the last response
+		var requestErr *RequestError
+		if client.rateLimiter != nil && errors.As(err, &requestErr) && requestErr.header != nil {
+			client.rateLimiter.Observe(ctx, method, path, requestErr.header)
+		}
+		return "", 0, interrupted(ctx, method, path, err)
+	}
+
+	tflog.Debug(ctx, "Received response", map[string]interface{}{"method": method, "path": path, "uri": baseURIs.uris[uriIndex], "status": resp.StatusCode, "request_id": requestID})
+
+	if client.rateLimiter != nil {
+		client.rateLimiter.Observe(ctx, method, path, resp.Header)
 	}
 
 	if client.debug || forceDebug {
@@ -348,12 +721,20 @@ func (client *APIClient) SendRequest(ctx context.Context, method string, path st
 	bodyBytes, err2 := io.ReadAll(resp.Body)
 	resp.Body.Close()
 	if err2 != nil {
-		return "", resp.StatusCode, err2
+		return "", resp.StatusCode, interrupted(ctx, method, path, err2)
 	}
 	body := strings.TrimPrefix(string(bodyBytes), client.xssiPrefix)
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return body, resp.StatusCode, fmt.Errorf("unexpected response code '%d': %s", resp.StatusCode, body)
+		return body, resp.StatusCode, &RequestError{
+			Method:          method,
+			Path:            path,
+			StatusCode:      resp.StatusCode,
+			Body:            body,
+			RequestID:       requestID,
+			ServerRequestID: resp.Header.Get(client.requestIDHeaderName()),
+			csrfRejected:    client.csrf != nil && client.csrf.rejected(resp, csrfSent),
+		}
 	}
 
 	// Empty response bodies are normalized to empty JSON objects for consistent parsing
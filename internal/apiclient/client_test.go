@@ -9,10 +9,12 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"math/big"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
@@ -711,3 +713,146 @@ func TestSendRequestConnectionError(t *testing.T) {
 	assert.Error(t, err, "Should return connection error")
 	assert.Contains(t, err.Error(), "connection refused", "Should contain connection refused error")
 }
+
+// TestSendRequestContextDeadline tests that the caller's deadline bounds the whole request, including retries
+func TestSendRequestContextDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, err := NewAPIClient(&APIClientOpt{
+		URI:          server.URL,
+		Timeout:      2,
+		RetryMax:     10,
+		RetryWaitMin: 5,
+		RetryWaitMax: 5,
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _, err = client.SendRequest(ctx, "GET", "/api/objects/1", "", false)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	var interruptedErr *RequestInterruptedError
+	assert.ErrorAs(t, err, &interruptedErr)
+	assert.Less(t, time.Since(start), 5*time.Second, "Retry backoff should be aborted by the deadline")
+}
+
+// TestSendRequestCancellation tests that cancelling the context aborts in-flight requests and OAuth token fetches
+func TestSendRequestCancellation(t *testing.T) {
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer slow.Close()
+	defer close(release)
+
+	tests := map[string]*APIClientOpt{
+		"request": {
+			URI:     slow.URL,
+			Timeout: 30,
+		},
+		"oauth_token": {
+			URI:               slow.URL,
+			Timeout:           30,
+			OAuthClientID:     "id",
+			OAuthClientSecret: "secret",
+			OAuthTokenURL:     slow.URL + "/token",
+		},
+	}
+
+	for name, opt := range tests {
+		t.Run(name, func(t *testing.T) {
+			client, err := NewAPIClient(opt)
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(100*time.Millisecond, cancel)
+
+			start := time.Now()
+			_, _, err = client.SendRequest(ctx, "GET", "/api/objects/1", "", false)
+			require.Error(t, err)
+			assert.ErrorIs(t, err, context.Canceled)
+			var interruptedErr *RequestInterruptedError
+			require.ErrorAs(t, err, &interruptedErr)
+			assert.Equal(t, "/api/objects/1", interruptedErr.Path)
+			assert.Less(t, time.Since(start), 5*time.Second, "Request should be aborted when the context is cancelled")
+		})
+	}
+}
+
+// TestSendRequestTimeoutIsNotInterruption tests that a per-request HTTP timeout is reported as an ordinary failure
+func TestSendRequestTimeoutIsNotInterruption(t *testing.T) {
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer slow.Close()
+	defer close(release)
+
+	client, err := NewAPIClient(&APIClientOpt{URI: slow.URL, Timeout: 1})
+	require.NoError(t, err)
+
+	_, _, err = client.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+	require.Error(t, err)
+	var interruptedErr *RequestInterruptedError
+	assert.False(t, errors.As(err, &interruptedErr))
+}
+
+// TestSendEndpointRequest tests that requests to a named endpoint use its base URI and headers
+func TestSendEndpointRequest(t *testing.T) {
+	handler := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(name + " " + r.URL.Path + " " + r.Header.Get("X-Region") + " " + r.Header.Get("Authorization")))
+		}
+	}
+	primary := httptest.NewServer(handler("primary"))
+	defer primary.Close()
+	regional := httptest.NewServer(handler("regional"))
+	defer regional.Close()
+
+	client, err := NewAPIClient(&APIClientOpt{
+		URI:     primary.URL,
+		Timeout: 2,
+		Headers: map[string]string{"Authorization": "Bearer token", "X-Region": "default"},
+		Endpoints: map[string]Endpoint{
+			"eu": {URI: regional.URL + "/v2/", Headers: map[string]string{"X-Region": "eu"}},
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, client.HasEndpoint("eu"))
+	assert.False(t, client.HasEndpoint("us"))
+
+	body, _, err := client.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+	require.NoError(t, err)
+	assert.Equal(t, "primary /api/objects/1 default Bearer token", body)
+
+	body, _, err = client.SendEndpointRequest(context.Background(), "eu", "GET", "/api/objects/1", "", false)
+	require.NoError(t, err)
+	assert.Equal(t, "regional /v2/api/objects/1 eu Bearer token", body, "Endpoint should set its base URI and headers over the client's")
+
+	_, _, err = client.SendEndpointRequest(context.Background(), "us", "GET", "/api/objects/1", "", false)
+	assert.ErrorContains(t, err, "endpoint 'us' is not configured")
+
+	_, err = NewAPIObject(client, &APIObjectOpts{Path: "/api/objects", ID: "1", Endpoint: "us"})
+	assert.ErrorContains(t, err, "endpoint 'us' is not configured")
+
+	obj, err := NewAPIObject(client, &APIObjectOpts{Path: "/api/objects", ID: "1", Endpoint: "eu"})
+	require.NoError(t, err)
+	body, _, err = obj.sendRequest(context.Background(), obj.readPath, "GET", "/api/objects/1", "")
+	require.NoError(t, err)
+	assert.Equal(t, "regional /v2/api/objects/1 eu Bearer token", body, "Object requests should go to its endpoint")
+
+	_, err = NewAPIClient(&APIClientOpt{URI: primary.URL, Endpoints: map[string]Endpoint{"empty": {}}})
+	assert.ErrorContains(t, err, "uri must be set for endpoint 'empty'")
+}
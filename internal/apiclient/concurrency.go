@@ -0,0 +1,100 @@
+package apiclient
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// concurrencyLimiter caps the number of requests in flight at any time across all resources
+// using the client. Read (safe) and write requests can be capped separately in addition
+// to an overall cap.
+type concurrencyLimiter struct {
+	total  chan struct{}
+	reads  chan struct{}
+	writes chan struct{}
+}
+
+// newConcurrencyLimiter builds the concurrency limiter for a client. Returns nil if no limits are configured.
+func newConcurrencyLimiter(ctx context.Context, opt *APIClientOpt) *concurrencyLimiter {
+	if opt.MaxConcurrentRequests <= 0 && opt.MaxConcurrentReads <= 0 && opt.MaxConcurrentWrites <= 0 {
+		return nil
+	}
+
+	tflog.Info(ctx, "concurrency limits configured", map[string]interface{}{
+		"maxConcurrentRequests": opt.MaxConcurrentRequests,
+		"maxConcurrentReads":    opt.MaxConcurrentReads,
+		"maxConcurrentWrites":   opt.MaxConcurrentWrites,
+	})
+
+	return &concurrencyLimiter{
+		total:  newSemaphore(opt.MaxConcurrentRequests),
+		reads:  newSemaphore(opt.MaxConcurrentReads),
+		writes: newSemaphore(opt.MaxConcurrentWrites),
+	}
+}
+
+// newSemaphore returns a semaphore with size slots, or nil (unlimited) if size is not positive
+func newSemaphore(size int64) chan struct{} {
+	if size <= 0 {
+		return nil
+	}
+	return make(chan struct{}, size)
+}
+
+// isSafeMethod reports whether the HTTP method is a read-only method: GET, HEAD or OPTIONS
+func isSafeMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// acquire blocks until a slot is available for the request or the context is done.
+// The returned function must be called to release the slot once the request is complete.
+func (c *concurrencyLimiter) acquire(ctx context.Context, method string) (func(), error) {
+	start := time.Now()
+
+	class, classSem := "write", c.writes
+	if isSafeMethod(method) {
+		class, classSem = "read", c.reads
+	}
+
+	// Take the per-class slot first so requests waiting for it don't hold one of the overall slots
+	if err := acquireSemaphore(ctx, classSem); err != nil {
+		return nil, err
+	}
+	if err := acquireSemaphore(ctx, c.total); err != nil {
+		releaseSemaphore(classSem)
+		return nil, err
+	}
+
+	tflog.Debug(ctx, "Acquired request slot", map[string]interface{}{"method": method, "class": class, "queue_wait": time.Since(start).String()})
+
+	return func() {
+		releaseSemaphore(c.total)
+		releaseSemaphore(classSem)
+	}, nil
+}
+
+func acquireSemaphore(ctx context.Context, sem chan struct{}) error {
+	if sem == nil {
+		return nil
+	}
+	select {
+	case sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func releaseSemaphore(sem chan struct{}) {
+	if sem != nil {
+		<-sem
+	}
+}
@@ -0,0 +1,96 @@
+package apiclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConcurrencyLimiter(t *testing.T) {
+	ctx := context.Background()
+
+	assert.Nil(t, newConcurrencyLimiter(ctx, &APIClientOpt{}), "No limiter when nothing is configured")
+
+	limiter := newConcurrencyLimiter(ctx, &APIClientOpt{MaxConcurrentWrites: 3})
+	require.NotNil(t, limiter)
+	assert.Nil(t, limiter.total, "Unset limits should not be enforced")
+	assert.Nil(t, limiter.reads, "Unset limits should not be enforced")
+	assert.Equal(t, 3, cap(limiter.writes))
+}
+
+// TestIsSafeMethod tests that only the methods documented as reads count as safe
+func TestIsSafeMethod(t *testing.T) {
+	for _, method := range []string{"GET", "head", "OPTIONS"} {
+		assert.True(t, isSafeMethod(method), method)
+	}
+	for _, method := range []string{"POST", "PUT", "PATCH", "DELETE", "TRACE"} {
+		assert.False(t, isSafeMethod(method), method)
+	}
+}
+
+func TestConcurrencyLimiterCancellation(t *testing.T) {
+	limiter := newConcurrencyLimiter(context.Background(), &APIClientOpt{MaxConcurrentRequests: 1})
+	require.NotNil(t, limiter)
+
+	release, err := limiter.acquire(context.Background(), "GET")
+	require.NoError(t, err)
+
+	// The only slot is taken, so the second request must give up when its context ends
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = limiter.acquire(ctx, "POST")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	release()
+	release2, err := limiter.acquire(context.Background(), "POST")
+	require.NoError(t, err, "Slot should be available after release")
+	release2()
+}
+
+func TestSendRequestMaxConcurrentWrites(t *testing.T) {
+	var inFlight, maxWrites int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			prev := atomic.LoadInt32(&maxWrites)
+			if current <= prev || atomic.CompareAndSwapInt32(&maxWrites, prev, current) {
+				break
+			}
+		}
+
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client, err := NewAPIClient(&APIClientOpt{
+		URI:                 server.URL,
+		Timeout:             5,
+		MaxConcurrentWrites: 2,
+	})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := client.SendRequest(context.Background(), "POST", fmt.Sprintf("/api/objects/%d", i), `{}`, false)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxWrites), int32(2), "No more than 2 writes should be in flight at once")
+}
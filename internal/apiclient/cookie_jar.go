@@ -0,0 +1,119 @@
+package apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// persistentJar is a cookie jar that is saved to a file, so sessions survive between runs.
+// Since cookiejar.Jar can't list its contents, the cookies set are also kept by the URL
+// they were received from, which is what is written to the file.
+type persistentJar struct {
+	*cookiejar.Jar
+	path    string
+	mux     sync.Mutex
+	cookies map[string][]*http.Cookie
+}
+
+// newPersistentJar creates a cookie jar backed by a file, loading any cookies saved in it
+func newPersistentJar(ctx context.Context, path string) (*persistentJar, error) {
+	jar, _ := cookiejar.New(nil)
+	j := &persistentJar{Jar: jar, path: path, cookies: map[string][]*http.Cookie{}}
+
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return j, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read cookie file: %w", err)
+	}
+	if err := json.Unmarshal(b, &j.cookies); err != nil {
+		return nil, fmt.Errorf("could not parse cookie file %s: %w", path, err)
+	}
+
+	for rawURL, cookies := range j.cookies {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse cookie file %s: %w", path, err)
+		}
+		j.Jar.SetCookies(u, cookies)
+	}
+	tflog.Debug(ctx, "Loaded cookie file", map[string]interface{}{"path": path, "urls": len(j.cookies)})
+	return j, nil
+}
+
+// SetCookies stores the cookies in the jar and saves them to the file
+func (j *persistentJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.Jar.SetCookies(u, cookies)
+
+	j.mux.Lock()
+	defer j.mux.Unlock()
+
+	key := (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}).String()
+	for _, cookie := range cookies {
+		saved := j.cookies[key][:0]
+		for _, c := range j.cookies[key] {
+			if c.Name != cookie.Name || c.Path != cookie.Path || c.Domain != cookie.Domain {
+				saved = append(saved, c)
+			}
+		}
+		// Max-Age is relative to when the cookie was received, so save it as an absolute expiry
+		c := *cookie
+		if c.MaxAge > 0 {
+			c.Expires = time.Now().Add(time.Duration(c.MaxAge) * time.Second)
+			c.MaxAge = 0
+		}
+		j.cookies[key] = append(saved, &c)
+	}
+
+	if err := j.save(); err != nil {
+		tflog.Warn(context.Background(), "Could not save cookie file", map[string]interface{}{"path": j.path, "error": err})
+	}
+}
+
+// save writes the cookies that have not expired to the file, readable only by the current user
+func (j *persistentJar) save() error {
+	now := time.Now()
+	live := map[string][]*http.Cookie{}
+	for key, cookies := range j.cookies {
+		for _, c := range cookies {
+			if c.MaxAge < 0 || (!c.Expires.IsZero() && c.Expires.Before(now)) {
+				continue
+			}
+			live[key] = append(live[key], c)
+		}
+	}
+	j.cookies = live
+
+	b, err := json.MarshalIndent(live, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file (created with mode 0600) and rename it over the
+	// cookie file so a concurrent run never reads a partially written file
+	tmp, err := os.CreateTemp(filepath.Dir(j.path), filepath.Base(j.path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), j.path)
+}
@@ -0,0 +1,119 @@
+package apiclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// credentialExpiryWindow is how long before their expiration credentials are refreshed, so a
+// request isn't sent with credentials that expire while it is in flight
+const credentialExpiryWindow = 30 * time.Second
+
+// CredentialProcess is a local command that prints the credentials to send with each request
+// as JSON on its standard output, in the form:
+//
+//	{"token": "...", "headers": {"X-Api-Key": "..."}, "expiration": "2006-01-02T15:04:05Z"}
+//
+// A token is sent as a bearer token in the Authorization header. The output is cached until
+// the expiration (or for the rest of the run if there is none) and the command is run again
+// when the API rejects the credentials with a 401.
+type CredentialProcess struct {
+	Command string
+	Args    []string
+	Env     map[string]string // Set in addition to the provider's own environment
+}
+
+// processCredentials is the output of a credential process
+type processCredentials struct {
+	Token      string            `json:"token"`
+	Headers    map[string]string `json:"headers"`
+	Expiration *time.Time        `json:"expiration"`
+}
+
+type credentialProcess struct {
+	CredentialProcess
+	mux   sync.Mutex
+	creds *processCredentials
+}
+
+func newCredentialProcess(opt *APIClientOpt) *credentialProcess {
+	if opt.CredentialProcess == nil || opt.CredentialProcess.Command == "" {
+		return nil
+	}
+	return &credentialProcess{CredentialProcess: *opt.CredentialProcess}
+}
+
+// headers returns the headers to set on a request, running the command if the cached
+// credentials have expired
+func (p *credentialProcess) headers(ctx context.Context) (map[string]string, error) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	if p.creds == nil || (p.creds.Expiration != nil && time.Until(*p.creds.Expiration) < credentialExpiryWindow) {
+		creds, err := p.run(ctx)
+		if err != nil {
+			return nil, err
+		}
+		p.creds = creds
+	}
+
+	headers := make(map[string]string, len(p.creds.Headers)+1)
+	for n, v := range p.creds.Headers {
+		headers[n] = v
+	}
+	if p.creds.Token != "" {
+		headers["Authorization"] = "Bearer " + p.creds.Token
+	}
+	return headers, nil
+}
+
+// invalidate drops the cached credentials so the command is run again for the next request
+func (p *credentialProcess) invalidate() {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.creds = nil
+}
+
+func (p *credentialProcess) run(ctx context.Context) (*processCredentials, error) {
+	tflog.Debug(ctx, "Running credential process", map[string]interface{}{"command": p.Command, "args": p.Args})
+
+	cmd := exec.CommandContext(ctx, p.Command, p.Args...)
+	cmd.Env = os.Environ()
+	for k, v := range p.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("credential process '%s' failed: %w: %s", p.Command, err, msg)
+		}
+		return nil, fmt.Errorf("credential process '%s' failed: %w", p.Command, err)
+	}
+
+	var creds processCredentials
+	if err := json.Unmarshal(stdout.Bytes(), &creds); err != nil {
+		return nil, fmt.Errorf("credential process '%s' returned invalid JSON: %w", p.Command, err)
+	}
+	if creds.Token == "" && len(creds.Headers) == 0 {
+		return nil, fmt.Errorf("credential process '%s' returned neither a token nor headers", p.Command)
+	}
+
+	fields := map[string]interface{}{"command": p.Command}
+	if creds.Expiration != nil {
+		fields["expiration"] = creds.Expiration.String()
+	}
+	tflog.Debug(ctx, "Credential process returned credentials", fields)
+	return &creds, nil
+}
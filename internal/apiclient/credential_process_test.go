@@ -0,0 +1,153 @@
+package apiclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCredentialProcessHelper is not a real test. It is run as the credential process by
+// the tests below: it records each run in a file and prints the configured output.
+func TestCredentialProcessHelper(t *testing.T) {
+	if os.Getenv("RESTAPI_CREDENTIAL_HELPER") != "1" {
+		t.Skip("only run as a credential process")
+	}
+	f, err := os.OpenFile(os.Getenv("RESTAPI_CREDENTIAL_RUNS"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err == nil {
+		f.WriteString("run\n")
+		f.Close()
+	}
+	if msg := os.Getenv("RESTAPI_CREDENTIAL_FAIL"); msg != "" {
+		fmt.Fprintln(os.Stderr, msg)
+		os.Exit(1)
+	}
+	fmt.Print(os.Getenv("RESTAPI_CREDENTIAL_OUTPUT"))
+	os.Exit(0)
+}
+
+// credentialProcessRuns returns how many times the helper credential process has run
+func credentialProcessRuns(t *testing.T, file string) int {
+	b, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return 0
+	}
+	require.NoError(t, err)
+	return strings.Count(string(b), "run\n")
+}
+
+func helperCredentialProcess(runsFile string, output string) *CredentialProcess {
+	return &CredentialProcess{
+		Command: os.Args[0],
+		Args:    []string{"-test.run=^TestCredentialProcessHelper$"},
+		Env: map[string]string{
+			"RESTAPI_CREDENTIAL_HELPER": "1",
+			"RESTAPI_CREDENTIAL_RUNS":   runsFile,
+			"RESTAPI_CREDENTIAL_OUTPUT": output,
+		},
+	}
+}
+
+// TestCredentialProcess tests that credentials from a credential process are sent and cached until they expire
+func TestCredentialProcess(t *testing.T) {
+	var gotAuth, gotKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotKey = r.Header.Get("X-Api-Key")
+	}))
+	defer server.Close()
+
+	tests := map[string]struct {
+		expiration time.Time
+		wantRuns   int
+	}{
+		"cached_until_expiration": {expiration: time.Now().Add(time.Hour), wantRuns: 1},
+		"expired":                 {expiration: time.Now().Add(time.Second), wantRuns: 2},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			runsFile := filepath.Join(t.TempDir(), "runs")
+			output := fmt.Sprintf(`{"token":"abc","headers":{"X-Api-Key":"key"},"expiration":"%s"}`, tc.expiration.Format(time.RFC3339))
+
+			client, err := NewAPIClient(&APIClientOpt{
+				URI:               server.URL,
+				Timeout:           2,
+				CredentialProcess: helperCredentialProcess(runsFile, output),
+			})
+			require.NoError(t, err)
+
+			for i := 0; i < 2; i++ {
+				_, _, err = client.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+				require.NoError(t, err)
+			}
+			assert.Equal(t, "Bearer abc", gotAuth)
+			assert.Equal(t, "key", gotKey)
+			assert.Equal(t, tc.wantRuns, credentialProcessRuns(t, runsFile))
+		})
+	}
+}
+
+// TestCredentialProcessUnauthorized tests that the credential process is run again when the API returns a 401
+func TestCredentialProcessUnauthorized(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer server.Close()
+
+	runsFile := filepath.Join(t.TempDir(), "runs")
+	client, err := NewAPIClient(&APIClientOpt{
+		URI:               server.URL,
+		Timeout:           2,
+		CredentialProcess: helperCredentialProcess(runsFile, `{"token":"abc"}`),
+	})
+	require.NoError(t, err)
+
+	body, status, err := client.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+	require.NoError(t, err)
+	assert.Equal(t, 200, status)
+	assert.Equal(t, `{"id":"1"}`, body)
+	assert.Equal(t, 2, credentialProcessRuns(t, runsFile))
+}
+
+// TestCredentialProcessErrors tests that a failing credential process fails the request
+func TestCredentialProcessErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	failing := helperCredentialProcess(filepath.Join(t.TempDir(), "runs"), "")
+	failing.Env["RESTAPI_CREDENTIAL_FAIL"] = "not logged in"
+
+	tests := map[string]struct {
+		process *CredentialProcess
+		wantErr string
+	}{
+		"command_fails":  {process: failing, wantErr: "not logged in"},
+		"invalid_json":   {process: helperCredentialProcess(filepath.Join(t.TempDir(), "runs"), "token"), wantErr: "returned invalid JSON"},
+		"no_credentials": {process: helperCredentialProcess(filepath.Join(t.TempDir(), "runs"), "{}"), wantErr: "returned neither a token nor headers"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			client, err := NewAPIClient(&APIClientOpt{URI: server.URL, Timeout: 2, CredentialProcess: tc.process})
+			require.NoError(t, err)
+
+			_, _, err = client.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
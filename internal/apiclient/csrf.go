@@ -0,0 +1,113 @@
+package apiclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// DefaultCSRFHeader is the header the CSRF token is sent in when none is configured
+const DefaultCSRFHeader = "X-CSRF-Token"
+
+// CSRFConfig configures how the CSRF token sent with unsafe (non GET/HEAD/OPTIONS)
+// requests is obtained: from a cookie, from the JSON response of a token path, or from a
+// cookie set by a request to the token path.
+type CSRFConfig struct {
+	CookieName string // Cookie holding the token
+	TokenPath  string // Path (relative to the base URI) to GET to obtain the token
+	TokenKey   string // Key of the token in the JSON response of TokenPath
+	HeaderName string // Header to send the token in (defaults to X-CSRF-Token)
+}
+
+// csrfTokens holds the CSRF tokens fetched from the token path, one per endpoint
+type csrfTokens struct {
+	CSRFConfig
+	mux    sync.Mutex
+	tokens map[string]string
+}
+
+func newCSRFTokens(opt *APIClientOpt) *csrfTokens {
+	if opt.CSRF == nil || (opt.CSRF.CookieName == "" && opt.CSRF.TokenPath == "") {
+		return nil
+	}
+	c := &csrfTokens{CSRFConfig: *opt.CSRF, tokens: map[string]string{}}
+	if c.HeaderName == "" {
+		c.HeaderName = DefaultCSRFHeader
+	}
+	return c
+}
+
+// token returns the CSRF token to send with a request to reqURL on the named endpoint, or an
+// empty string if the token cookie hasn't been set yet
+func (c *csrfTokens) token(ctx context.Context, client *APIClient, endpoint string, reqURL *url.URL) (string, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.TokenPath != "" {
+		if _, fetched := c.tokens[endpoint]; !fetched {
+			// The token path is read with GET, which never needs a CSRF token itself
+			tflog.Debug(ctx, "Fetching CSRF token", map[string]interface{}{"path": c.TokenPath, "endpoint": endpoint})
+			body, _, err := client.SendEndpointRequest(ctx, endpoint, "GET", c.TokenPath, "", false)
+			if err != nil {
+				return "", fmt.Errorf("failed to fetch CSRF token: %w", err)
+			}
+
+			token := ""
+			if c.TokenKey != "" {
+				var data map[string]interface{}
+				if err := DecodeJSON([]byte(body), &data); err != nil {
+					return "", fmt.Errorf("failed to parse CSRF token response: %w", err)
+				}
+				if token, err = GetStringAtKey(ctx, data, c.TokenKey); err != nil {
+					return "", fmt.Errorf("failed to find CSRF token in response: %w", err)
+				}
+			}
+			c.tokens[endpoint] = token
+		}
+		if c.TokenKey != "" {
+			return c.tokens[endpoint], nil
+		}
+	}
+
+	// Otherwise, the token is the cookie, as set by the token path or any earlier response
+	if client.cookieJar != nil {
+		for _, cookie := range client.cookieJar.Cookies(reqURL) {
+			if cookie.Name == c.CookieName {
+				return cookie.Value, nil
+			}
+		}
+	}
+	tflog.Debug(ctx, "CSRF cookie not set yet", map[string]interface{}{"cookie_name": c.CookieName})
+	return "", nil
+}
+
+// invalidate drops the token of an endpoint so it is fetched again for the next request
+func (c *csrfTokens) invalidate(endpoint string) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	delete(c.tokens, endpoint)
+}
+
+// rejected reports whether resp is the API rejecting the CSRF token of a request. A 403 is only
+// blamed on the token if one was sent, or if the API sets the token cookie along with it (as
+// when the first request of a session is made before the cookie exists).
+func (c *csrfTokens) rejected(resp *http.Response, sent bool) bool {
+	if resp.StatusCode != http.StatusForbidden {
+		return false
+	}
+	if sent {
+		return true
+	}
+	if c.CookieName != "" {
+		for _, cookie := range resp.Cookies() {
+			if cookie.Name == c.CookieName {
+				return true
+			}
+		}
+	}
+	return false
+}
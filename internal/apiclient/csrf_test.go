@@ -0,0 +1,173 @@
+package apiclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCookieFile tests that cookies are saved to the cookie file and loaded by later clients
+func TestCookieFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/", MaxAge: 3600})
+			http.SetCookie(w, &http.Cookie{Name: "expired", Value: "old", Path: "/", MaxAge: -1})
+		default:
+			cookie, err := r.Cookie("session")
+			if err != nil || cookie.Value != "abc" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}
+	}))
+	defer server.Close()
+
+	cookieFile := filepath.Join(t.TempDir(), "cookies.json")
+
+	client, err := NewAPIClient(&APIClientOpt{URI: server.URL, Timeout: 2, CookieFile: cookieFile})
+	require.NoError(t, err)
+	_, _, err = client.SendRequest(context.Background(), "POST", "/login", "", false)
+	require.NoError(t, err)
+
+	info, err := os.Stat(cookieFile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "The cookie file should only be readable by the current user")
+	b, err := os.ReadFile(cookieFile)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "expired", "Expired cookies should not be saved")
+
+	// A new client (as in the next Terraform run) reuses the session
+	client, err = NewAPIClient(&APIClientOpt{URI: server.URL, Timeout: 2, CookieFile: cookieFile})
+	require.NoError(t, err)
+	_, status, err := client.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+
+	require.NoError(t, os.WriteFile(cookieFile, []byte("not json"), 0600))
+	_, err = NewAPIClient(&APIClientOpt{URI: server.URL, CookieFile: cookieFile})
+	assert.ErrorContains(t, err, "could not parse cookie file")
+}
+
+// csrfServer is an API that requires the current CSRF token on unsafe requests
+type csrfServer struct {
+	mux      sync.Mutex
+	token    int
+	fetches  int
+	rejected int
+}
+
+func (s *csrfServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	current := fmt.Sprintf("token-%d", s.token)
+	switch {
+	case r.URL.Path == "/csrf":
+		s.fetches++
+		http.SetCookie(w, &http.Cookie{Name: "csrftoken", Value: current, Path: "/"})
+		fmt.Fprintf(w, `{"csrf":{"token":"%s"}}`, current)
+	case r.Method == http.MethodGet:
+		w.Write([]byte(`{}`))
+	case r.Header.Get("X-CSRF-Token") != current:
+		s.rejected++
+		http.SetCookie(w, &http.Cookie{Name: "csrftoken", Value: current, Path: "/"})
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error":"CSRF token missing or incorrect"}`))
+	default:
+		w.Write([]byte(`{"id":"1"}`))
+	}
+}
+
+// rotate invalidates the current token, as a server does when a session expires
+func (s *csrfServer) rotate() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.token++
+}
+
+// TestCSRF tests that the CSRF token is sent with unsafe requests and refreshed when the API rejects it
+func TestCSRF(t *testing.T) {
+	tests := map[string]struct {
+		csrf        CSRFConfig
+		wantFetches int
+	}{
+		"cookie":            {csrf: CSRFConfig{CookieName: "csrftoken"}, wantFetches: 0},
+		"token_path":        {csrf: CSRFConfig{TokenPath: "/csrf", TokenKey: "csrf/token"}, wantFetches: 2},
+		"token_path_cookie": {csrf: CSRFConfig{TokenPath: "/csrf", CookieName: "csrftoken"}, wantFetches: 2},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			api := &csrfServer{}
+			server := httptest.NewServer(api)
+			defer server.Close()
+
+			csrf := tc.csrf
+			client, err := NewAPIClient(&APIClientOpt{URI: server.URL, Timeout: 2, CSRF: &csrf})
+			require.NoError(t, err)
+
+			_, _, err = client.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+			require.NoError(t, err)
+			_, _, err = client.SendRequest(context.Background(), "POST", "/api/objects", `{"id":"1"}`, false)
+			require.NoError(t, err)
+
+			api.rotate()
+			body, _, err := client.SendRequest(context.Background(), "PUT", "/api/objects/1", `{"id":"1"}`, false)
+			require.NoError(t, err, "A rejected CSRF token should be refreshed")
+			assert.Equal(t, `{"id":"1"}`, body)
+			assert.Equal(t, tc.wantFetches, api.fetches)
+		})
+	}
+}
+
+// TestCSRFForbidden tests that a 403 unrelated to the CSRF token is not retried
+func TestCSRFForbidden(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error":"permission denied"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewAPIClient(&APIClientOpt{URI: server.URL, Timeout: 2, CSRF: &CSRFConfig{CookieName: "csrftoken"}})
+	require.NoError(t, err)
+
+	_, status, err := client.SendRequest(context.Background(), "POST", "/api/objects", `{"id":"1"}`, false)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Equal(t, 1, requests, "A 403 without a CSRF token sent or set should not be retried")
+}
+
+// TestCSRFTokenPathError tests that a failed token fetch is reported against the request that needed the token
+func TestCSRFTokenPathError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := NewAPIClient(&APIClientOpt{URI: server.URL, Timeout: 2, CSRF: &CSRFConfig{TokenPath: "/csrf", TokenKey: "token"}})
+	require.NoError(t, err)
+
+	_, _, err = client.SendRequest(context.Background(), "POST", "/api/objects", `{"id":"1"}`, false)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "failed to fetch CSRF token")
+
+	var requestErr *RequestError
+	require.ErrorAs(t, err, &requestErr)
+	assert.Equal(t, "POST", requestErr.Method)
+	assert.Equal(t, "/api/objects", requestErr.Path)
+
+	var tokenErr *RequestError
+	require.ErrorAs(t, requestErr.Err, &tokenErr)
+	assert.Equal(t, "GET", tokenErr.Method)
+	assert.Equal(t, "/csrf", tokenErr.Path)
+}
@@ -0,0 +1,34 @@
+package apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Destroy policies decide what DeleteObject does with the object
+const (
+	DestroyPolicyDelete  = "delete"  // Delete the object
+	DestroyPolicyAbandon = "abandon" // Leave the object as it is on the API
+	DestroyPolicyProtect = "protect" // Refuse to destroy the object
+	DestroyPolicyReset   = "reset"   // Send the reset data to the update path, as for singleton objects
+)
+
+// DestroyPolicies lists the valid destroy policies
+var DestroyPolicies = []string{DestroyPolicyDelete, DestroyPolicyAbandon, DestroyPolicyProtect, DestroyPolicyReset}
+
+// resetObject sends the reset data to the update path with the reset method
+func (obj *APIObject) resetObject(ctx context.Context) error {
+	putPath := obj.updatePath
+	if obj.queryString != "" {
+		tflog.Debug(ctx, "Adding query string", map[string]interface{}{"query_string": obj.queryString})
+		putPath = fmt.Sprintf("%s?%s", obj.updatePath, obj.queryString)
+	}
+
+	resetData, _ := json.Marshal(obj.resetData)
+	tflog.Info(ctx, "Resetting object instead of deleting it", map[string]interface{}{"id": obj.ID, "reset_data": string(resetData)})
+	_, _, err := obj.sendRequest(ctx, obj.updatePath, obj.resetMethod, putPath, string(resetData))
+	return err
+}
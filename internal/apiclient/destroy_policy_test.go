@@ -0,0 +1,94 @@
+package apiclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDestroyPolicy tests what DeleteObject sends for each destroy policy
+func TestDestroyPolicy(t *testing.T) {
+	tests := map[string]struct {
+		policy       string
+		resetMethod  string
+		wantErr      string
+		wantRequests []string
+		wantBody     string
+	}{
+		"default": {
+			wantRequests: []string{"DELETE /api/objects/1"},
+		},
+		"delete": {
+			policy:       DestroyPolicyDelete,
+			wantRequests: []string{"DELETE /api/objects/1"},
+		},
+		"abandon": {
+			policy: DestroyPolicyAbandon,
+		},
+		"protect": {
+			policy:  DestroyPolicyProtect,
+			wantErr: "protected by destroy_policy 'protect'",
+		},
+		"reset": {
+			policy:       DestroyPolicyReset,
+			wantRequests: []string{"PATCH /api/objects/1"},
+			wantBody:     `{"enabled":false}`,
+		},
+		"reset_method": {
+			policy:       DestroyPolicyReset,
+			resetMethod:  "PUT",
+			wantRequests: []string{"PUT /api/objects/1"},
+			wantBody:     `{"enabled":false}`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var requests []string
+			var body string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				b, _ := io.ReadAll(r.Body)
+				body = string(b)
+			}))
+			defer server.Close()
+
+			client, err := NewAPIClient(&APIClientOpt{URI: server.URL, Timeout: 2})
+			require.NoError(t, err)
+
+			opts := &APIObjectOpts{
+				Path:          "/api/objects",
+				ID:            "1",
+				UpdateMethod:  "PATCH",
+				DestroyPolicy: tc.policy,
+				ResetMethod:   tc.resetMethod,
+			}
+			if tc.policy == DestroyPolicyReset {
+				opts.ResetData = `{"enabled":false}`
+			}
+			obj, err := NewAPIObject(client, opts)
+			require.NoError(t, err)
+
+			err = obj.DeleteObject(context.Background())
+			assert.Equal(t, tc.wantRequests, requests)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantBody, body)
+		})
+	}
+
+	client, err := NewAPIClient(&APIClientOpt{URI: "http://127.0.0.1:8083", Timeout: 2})
+	require.NoError(t, err)
+	_, err = NewAPIObject(client, &APIObjectOpts{Path: "/api/objects", ID: "1", DestroyPolicy: "keep"})
+	assert.ErrorContains(t, err, "destroy_policy 'keep' is not valid")
+	_, err = NewAPIObject(client, &APIObjectOpts{Path: "/api/objects", ID: "1", DestroyPolicy: DestroyPolicyReset})
+	assert.ErrorContains(t, err, "requires reset_data")
+}
@@ -0,0 +1,64 @@
+package apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// envelopeKeys splits an envelope key into the keys the object is nested under. Like
+// results_key, it is a path of keys separated by slashes (data/item) or a JSON Pointer.
+func envelopeKeys(name string, key string) ([]string, error) {
+	if key == "" {
+		return nil, nil
+	}
+	if IsJSONPointer(key) {
+		keys, err := ParseJSONPointer(key)
+		if err != nil {
+			return nil, fmt.Errorf("%s '%s' is not valid: %w", name, key, err)
+		}
+		return keys, nil
+	}
+
+	var keys []string
+	for _, part := range strings.Split(key, "/") {
+		if part != "" {
+			keys = append(keys, part)
+		}
+	}
+	return keys, nil
+}
+
+// unwrapResponse returns the object inside the response envelope, if response_envelope_key
+// is set, so that the object is stored and compared without the envelope around it
+func (obj *APIObject) unwrapResponse(ctx context.Context, response string) (string, error) {
+	if obj.responseEnvelopeKey == "" {
+		return response, nil
+	}
+
+	var envelope map[string]interface{}
+	if err := DecodeJSON([]byte(response), &envelope); err != nil {
+		return "", fmt.Errorf("failed to parse the response to find response_envelope_key '%s': %w", obj.responseEnvelopeKey, err)
+	}
+	inner, err := GetObjectAtKey(ctx, envelope, obj.responseEnvelopeKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to find response_envelope_key '%s' in the response: %w", obj.responseEnvelopeKey, err)
+	}
+	tflog.Debug(ctx, "Unwrapping object from response envelope", map[string]interface{}{"response_envelope_key": obj.responseEnvelopeKey})
+	b, err := json.Marshal(inner)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// wrapRequest nests data in the request envelope, if request_envelope_key is set
+func (obj *APIObject) wrapRequest(data interface{}) interface{} {
+	for i := len(obj.requestEnvelope) - 1; i >= 0; i-- {
+		data = map[string]interface{}{obj.requestEnvelope[i]: data}
+	}
+	return data
+}
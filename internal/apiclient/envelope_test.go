@@ -0,0 +1,98 @@
+package apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// envelopeServer is an API that returns objects as {"data": {...}, "meta": {...}} and
+// takes them as {"item": {...}}
+type envelopeServer struct {
+	mux    sync.Mutex
+	object map[string]interface{}
+	bodies []string
+}
+
+func (s *envelopeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if r.Method == http.MethodPost || r.Method == http.MethodPut {
+		b, _ := io.ReadAll(r.Body)
+		s.bodies = append(s.bodies, string(b))
+		var o map[string]map[string]interface{}
+		json.Unmarshal(b, &o)
+		s.object = o["item"]
+		s.object["id"] = "1"
+	}
+	if s.object == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"data": s.object, "meta": map[string]interface{}{"request": len(s.bodies)}})
+}
+
+// TestEnvelope tests that objects are unwrapped from response envelopes and wrapped in
+// request envelopes
+func TestEnvelope(t *testing.T) {
+	for _, strategy := range []string{UpdateStrategyFull, UpdateStrategyJSONPatch} {
+		api := &envelopeServer{}
+		server := httptest.NewServer(api)
+		defer server.Close()
+
+		// The object's envelope keys override the client's
+		client, err := NewAPIClient(&APIClientOpt{URI: server.URL, Timeout: 2, WriteReturnsObject: true, ResponseEnvelopeKey: "data", RequestEnvelopeKey: "wrong"})
+		require.NoError(t, err)
+
+		obj, err := NewAPIObject(client, &APIObjectOpts{
+			Path:               "/api/objects",
+			Data:               `{"name":"foo","size":1}`,
+			UpdateStrategy:     strategy,
+			RequestEnvelopeKey: "/item",
+		})
+		require.NoError(t, err)
+
+		ctx := context.Background()
+		require.NoError(t, obj.CreateObject(ctx))
+		assert.JSONEq(t, `{"item":{"name":"foo","size":1}}`, api.bodies[0])
+		assert.Equal(t, "1", obj.ID)
+		assert.JSONEq(t, `{"id":"1","name":"foo","size":1}`, obj.GetApiResponse())
+
+		require.NoError(t, obj.ReadObject(ctx))
+		assert.Equal(t, map[string]string{"id": "1", "name": "foo", "size": "1"}, obj.GetApiData())
+
+		require.NoError(t, obj.SetPriorData(`{"name":"foo","size":1}`))
+		obj.data = map[string]interface{}{"name": "foo", "size": 2}
+		body, _, _, err := obj.updatePayload()
+		require.NoError(t, err)
+		if strategy == UpdateStrategyJSONPatch {
+			assert.JSONEq(t, `[{"op":"replace","path":"/item/size","value":2}]`, body)
+			continue
+		}
+		assert.JSONEq(t, `{"item":{"name":"foo","size":2}}`, body)
+
+		require.NoError(t, obj.UpdateObject(ctx))
+		assert.Equal(t, map[string]string{"id": "1", "name": "foo", "size": "2"}, obj.GetApiData())
+	}
+
+	client, err := NewAPIClient(&APIClientOpt{URI: "http://127.0.0.1:8083", Timeout: 2})
+	require.NoError(t, err)
+	_, err = NewAPIObject(client, &APIObjectOpts{Path: "/api/objects", ID: "1", RequestEnvelopeKey: "/it~em"})
+	assert.ErrorContains(t, err, "request_envelope_key '/it~em' is not valid")
+
+	obj, err := NewAPIObject(client, &APIObjectOpts{Path: "/api/objects", ID: "1", ResponseEnvelopeKey: "data/item"})
+	require.NoError(t, err)
+	_, err = obj.unwrapResponse(context.Background(), `{"data":{"id":"1"}}`)
+	assert.ErrorContains(t, err, "failed to find response_envelope_key 'data/item' in the response")
+	inner, err := obj.unwrapResponse(context.Background(), `{"data":{"item":{"id":"1"}}}`)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"1"}`, inner)
+}
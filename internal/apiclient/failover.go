@@ -0,0 +1,105 @@
+package apiclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// failoverGroup is the ordered list of base URIs a request can be sent to. Requests go to the
+// active URI and move down the list when it cannot be reached; the URI that answers becomes the
+// active one for the rest of the run.
+type failoverGroup struct {
+	mux    sync.Mutex
+	uris   []string
+	active int
+}
+
+// newFailoverGroup makes the group of a base URI and the URIs to fail over to after it
+func newFailoverGroup(uri string, failoverURIs []string) *failoverGroup {
+	group := &failoverGroup{}
+	for _, u := range append([]string{uri}, failoverURIs...) {
+		// Remove any trailing slashes since we will append
+		// to this URL with our own root-prefixed location
+		group.uris = append(group.uris, strings.TrimSuffix(u, "/"))
+	}
+	return group
+}
+
+func (g *failoverGroup) activeIndex() int {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	return g.active
+}
+
+// setActive makes the URI at index i the first one tried by later requests
+func (g *failoverGroup) setActive(ctx context.Context, i int) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	if g.active == i {
+		return
+	}
+	tflog.Warn(ctx, "Failed over to another URI, which will be used for the remaining requests", map[string]interface{}{"from": g.uris[g.active], "to": g.uris[i]})
+	g.active = i
+}
+
+// shouldFailOver reports whether a request should be sent to the next URI: the request could not
+// be sent (connection errors and timeouts) or the API answered with one of the failover status codes
+func (client *APIClient) shouldFailOver(ctx context.Context, resp *http.Response, err error) bool {
+	// A cancelled request says nothing about the health of the URI
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		var requestErr *RequestError
+		if errors.As(err, &requestErr) {
+			// Retries were exhausted on an error response
+			return slices.Contains(client.failoverStatusCodes, requestErr.StatusCode)
+		}
+		return true
+	}
+	return slices.Contains(client.failoverStatusCodes, resp.StatusCode)
+}
+
+// failoverPendingKey marks the context of a request that still has URIs to fail over to
+type failoverPendingKey struct{}
+
+func withFailoverPending(ctx context.Context, pending bool) context.Context {
+	return context.WithValue(ctx, failoverPendingKey{}, pending)
+}
+
+// failoverRetryPolicy is the retry policy of the HTTP client. A request that could not be sent
+// is not retried while there is another URI to fail over to: the next URI is tried right away
+// rather than after the whole retry budget has been spent on a host that is down.
+func failoverRetryPolicy(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if pending, _ := ctx.Value(failoverPendingKey{}).(bool); pending && err != nil && ctx.Err() == nil {
+		return false, nil
+	}
+	return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+}
+
+// setRequestURI points a request at a different URI, such as the same path on another base URI
+func setRequestURI(req *retryablehttp.Request, uri string) error {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return err
+	}
+	req.URL = u
+	req.Host = u.Host
+	return nil
+}
+
+// responseStatus returns the status code of resp, or 0 if there is no response
+func responseStatus(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
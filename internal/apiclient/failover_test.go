@@ -0,0 +1,145 @@
+package apiclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewFailoverGroup tests that the base URI comes first in the group, followed by the URIs to fail over to
+func TestNewFailoverGroup(t *testing.T) {
+	group := newFailoverGroup("https://primary.example.com/", []string{"https://secondary.example.com", "https://tertiary.example.com/a,b"})
+	assert.Equal(t, []string{"https://primary.example.com", "https://secondary.example.com", "https://tertiary.example.com/a,b"}, group.uris)
+	assert.Equal(t, 0, group.activeIndex())
+
+	_, err := NewAPIClient(&APIClientOpt{URI: "https://primary.example.com", Endpoints: map[string]Endpoint{"v2": {FailoverURIs: []string{"https://secondary.example.com"}}}})
+	assert.Error(t, err, "An endpoint without a URI should be rejected")
+}
+
+// TestFailoverConnectionError tests that requests fail over to the next URI when one can't be reached, and stay there
+func TestFailoverConnectionError(t *testing.T) {
+	var secondaryHits int32
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&secondaryHits, 1)
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer secondary.Close()
+
+	var primaryHits int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&primaryHits, 1)
+	}))
+	primaryURL := primary.URL
+	primary.Close() // Nothing listens on the primary any more
+
+	client, err := NewAPIClient(&APIClientOpt{
+		URI:          primaryURL,
+		FailoverURIs: []string{secondary.URL},
+		Timeout:      2,
+	})
+	require.NoError(t, err)
+
+	body, status, err := client.SendRequest(context.Background(), "POST", "/api/objects", `{"id":"1"}`, false)
+	require.NoError(t, err)
+	assert.Equal(t, 200, status)
+	assert.Equal(t, `{"id":"1"}`, body)
+	assert.Equal(t, 1, client.failover[""].activeIndex(), "The healthy URI should become the active one")
+
+	_, _, err = client.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&secondaryHits))
+	assert.Zero(t, atomic.LoadInt32(&primaryHits))
+}
+
+// TestFailoverBeforeRetry tests that a connection error moves on to the next URI at once instead of
+// retrying the URI that can't be reached, while the last URI still gets the retries
+func TestFailoverBeforeRetry(t *testing.T) {
+	var secondaryHits int32
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&secondaryHits, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer secondary.Close()
+
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	primaryURL := primary.URL
+	primary.Close()
+
+	client, err := NewAPIClient(&APIClientOpt{
+		URI:          primaryURL,
+		FailoverURIs: []string{secondary.URL},
+		Timeout:      2,
+		RetryMax:     3,
+		RetryWaitMin: 1,
+		RetryWaitMax: 1,
+	})
+	require.NoError(t, err)
+
+	start := time.Now()
+	body, _, err := client.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"1"}`, body)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&secondaryHits), "The last URI should be retried")
+	assert.Less(t, time.Since(start), 2*time.Second, "The URI that can't be reached should not be retried before failing over")
+}
+
+// TestFailoverStatusCodes tests that only the configured status codes cause a failover
+func TestFailoverStatusCodes(t *testing.T) {
+	var primaryStatus int32 = http.StatusServiceUnavailable
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(atomic.LoadInt32(&primaryStatus)))
+	}))
+	defer primary.Close()
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`secondary`))
+	}))
+	defer secondary.Close()
+
+	newClient := func() *APIClient {
+		client, err := NewAPIClient(&APIClientOpt{
+			URI:                 primary.URL,
+			FailoverURIs:        []string{secondary.URL},
+			Timeout:             2,
+			FailoverStatusCodes: []int{http.StatusServiceUnavailable},
+		})
+		require.NoError(t, err)
+		return client
+	}
+
+	client := newClient()
+	body, _, err := client.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+	require.NoError(t, err)
+	assert.Equal(t, "secondary", body)
+
+	atomic.StoreInt32(&primaryStatus, http.StatusInternalServerError)
+	client = newClient()
+	_, _, err = client.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+	var requestErr *RequestError
+	require.ErrorAs(t, err, &requestErr)
+	assert.Equal(t, http.StatusInternalServerError, requestErr.StatusCode, "Status codes not configured for failover should be returned as-is")
+	assert.Equal(t, 0, client.failover[""].activeIndex())
+}
+
+// TestFailoverAllURIsDown tests that the last failure is returned when no URI can be reached
+func TestFailoverAllURIsDown(t *testing.T) {
+	client, err := NewAPIClient(&APIClientOpt{
+		URI:          "http://127.0.0.1:9998",
+		FailoverURIs: []string{"http://127.0.0.1:9999"},
+		Timeout:      2,
+	})
+	require.NoError(t, err)
+
+	_, _, err = client.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "127.0.0.1:9999")
+	assert.Equal(t, 0, client.failover[""].activeIndex(), "The active URI should not change if no URI answered")
+}
@@ -0,0 +1,90 @@
+package apiclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// DecodeJSON is json.Unmarshal, except that numbers in interface{} values are decoded as
+// json.Number rather than float64, so that integers above 2^53 keep their precision
+func DecodeJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	// Like json.Unmarshal, reject anything after the value
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid character after top-level value")
+	}
+	return nil
+}
+
+// NumbersEqual reports whether two JSON numbers have the same value, however they are
+// written, such as 1, 1.0 and 1e0
+func NumbersEqual(a, b json.Number) bool {
+	if a == b {
+		return true
+	}
+	x, okX := new(big.Rat).SetString(string(a))
+	y, okY := new(big.Rat).SetString(string(b))
+	return okX && okY && x.Cmp(y) == 0
+}
+
+// JSONEqual deep compares two decoded JSON values, comparing numbers by value, so that a
+// json.Number and a float64 or int of the same value are equal
+func JSONEqual(a, b interface{}) bool {
+	switch x := a.(type) {
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			if w, ok := y[k]; !ok || !JSONEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !JSONEqual(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if x, ok := jsonNumber(a); ok {
+		y, ok := jsonNumber(b)
+		return ok && NumbersEqual(x, y)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// jsonNumber returns a number decoded as a json.Number or a Go number as a json.Number
+func jsonNumber(v interface{}) (json.Number, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		return n, true
+	case float64:
+		return json.Number(strconv.FormatFloat(n, 'g', -1, 64)), true
+	case float32:
+		return json.Number(strconv.FormatFloat(float64(n), 'g', -1, 32)), true
+	case int:
+		return json.Number(strconv.Itoa(n)), true
+	case int64:
+		return json.Number(strconv.FormatInt(n, 10)), true
+	}
+	return "", false
+}
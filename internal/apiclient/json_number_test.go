@@ -0,0 +1,76 @@
+package apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDecodeJSON tests that numbers are decoded as json.Number and trailing data is rejected
+func TestDecodeJSON(t *testing.T) {
+	var data map[string]interface{}
+	require.NoError(t, DecodeJSON([]byte(`{"id":9007199254740993,"ratio":0.1}`), &data))
+	assert.Equal(t, json.Number("9007199254740993"), data["id"])
+	assert.Equal(t, json.Number("0.1"), data["ratio"])
+
+	assert.Error(t, DecodeJSON([]byte(`{"id":1} {"id":2}`), &data))
+	assert.Error(t, DecodeJSON([]byte(`{"id":`), &data))
+}
+
+// TestJSONEqual tests that numbers are compared by value
+func TestJSONEqual(t *testing.T) {
+	assert.True(t, NumbersEqual("1", "1.0"))
+	assert.True(t, NumbersEqual("100", "1e2"))
+	assert.False(t, NumbersEqual("9007199254740993", "9007199254740992"))
+
+	assert.True(t, JSONEqual(json.Number("1"), float64(1)))
+	assert.True(t, JSONEqual(json.Number("2"), 2))
+	assert.True(t, JSONEqual(
+		map[string]interface{}{"a": []interface{}{json.Number("1.50"), "x"}},
+		map[string]interface{}{"a": []interface{}{1.5, "x"}},
+	))
+	assert.False(t, JSONEqual(json.Number("42"), "42"))
+	assert.False(t, JSONEqual([]interface{}{json.Number("1")}, []interface{}{json.Number("1"), json.Number("2")}))
+	assert.False(t, JSONEqual(map[string]interface{}{"a": nil}, map[string]interface{}{"b": nil}))
+}
+
+// TestLargeIntegerID tests that an id above 2^53 is kept as it is from the response to the request path
+func TestLargeIntegerID(t *testing.T) {
+	var paths []string
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		w.Write([]byte(`{"id":9007199254740993,"size":12345678901234567890}`))
+	}))
+	defer server.Close()
+
+	client, err := NewAPIClient(&APIClientOpt{URI: server.URL, Timeout: 2, WriteReturnsObject: true, IDAttribute: "id"})
+	require.NoError(t, err)
+
+	obj, err := NewAPIObject(client, &APIObjectOpts{
+		Path: "/api/objects",
+		Data: `{"size":12345678901234567890}`,
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, obj.CreateObject(ctx))
+	assert.Equal(t, "9007199254740993", obj.ID)
+	assert.Equal(t, `{"size":12345678901234567890}`, bodies[0])
+	assert.Equal(t, "12345678901234567890", obj.GetApiData()["size"])
+
+	size, err := GetStringAtKey(ctx, asObject(obj.apiData), "size")
+	require.NoError(t, err)
+	assert.Equal(t, "12345678901234567890", size)
+
+	require.NoError(t, obj.ReadObject(ctx))
+	assert.Equal(t, "/api/objects/9007199254740993", paths[1])
+}
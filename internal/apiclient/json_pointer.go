@@ -0,0 +1,155 @@
+package apiclient
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// IsJSONPointer reports whether a key path is a JSON Pointer (RFC 6901), such as
+// `/metadata/name` or `/tags/0`, rather than the legacy `field/field` syntax. JSON
+// Pointers start with a slash.
+func IsJSONPointer(path string) bool {
+	return strings.HasPrefix(path, "/")
+}
+
+// escapeJSONPointer escapes a key for use as a JSON Pointer (RFC 6901) reference token
+func escapeJSONPointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+// ParseJSONPointer splits a JSON Pointer into its unescaped reference tokens, so that
+// `/a~1b/c~0d` is ["a/b", "c~d"]. The empty pointer refers to the whole document.
+func ParseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !IsJSONPointer(pointer) {
+		return nil, fmt.Errorf("JSON pointer '%s' must start with '/'", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		// ~ may only be followed by 0 or 1
+		for j := 0; j < len(token); j++ {
+			if token[j] == '~' && (j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1')) {
+				return nil, fmt.Errorf("JSON pointer '%s' has an invalid escape in '%s': '~' must be followed by '0' or '1'", pointer, token)
+			}
+		}
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+// GetObjectAtPointer finds the value a JSON Pointer refers to, indexing arrays by position
+func GetObjectAtPointer(data interface{}, pointer string) (interface{}, error) {
+	tokens, err := ParseJSONPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	current := data
+	for i, token := range tokens {
+		seen := "/" + strings.Join(escapeTokens(tokens[:i]), "/")
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("JSON pointer '%s': the object at '%s' does not have key '%s'. Available: %s", pointer, seen, token, strings.Join(GetKeys(node), ","))
+			}
+			current = value
+		case []interface{}:
+			index, err := arrayIndex(token, len(node))
+			if err != nil {
+				return nil, fmt.Errorf("JSON pointer '%s': %w in the array at '%s'", pointer, err, seen)
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("JSON pointer '%s': the value at '%s' is not an object or array", pointer, seen)
+		}
+	}
+	return current, nil
+}
+
+// SetObjectAtPointer sets the value a JSON Pointer refers to, creating missing objects
+// along the way. Array elements must already exist.
+func SetObjectAtPointer(data interface{}, pointer string, value interface{}) error {
+	tokens, err := ParseJSONPointer(pointer)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return fmt.Errorf("JSON pointer '%s' must refer to a field", pointer)
+	}
+
+	current := data
+	for i, token := range tokens {
+		last := i == len(tokens)-1
+		switch node := current.(type) {
+		case map[string]interface{}:
+			if last {
+				node[token] = value
+				return nil
+			}
+			if _, ok := node[token]; !ok || node[token] == nil {
+				node[token] = map[string]interface{}{}
+			}
+			current = node[token]
+		case []interface{}:
+			index, err := arrayIndex(token, len(node))
+			if err != nil {
+				return fmt.Errorf("JSON pointer '%s': %w", pointer, err)
+			}
+			if last {
+				node[index] = value
+				return nil
+			}
+			current = node[index]
+		default:
+			return fmt.Errorf("JSON pointer '%s': the value at '/%s' is not an object or array", pointer, strings.Join(escapeTokens(tokens[:i]), "/"))
+		}
+	}
+	return nil
+}
+
+// arrayIndex parses a reference token as an index into an array of the given length
+func arrayIndex(token string, length int) (int, error) {
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("'%s' is not a valid array index", token)
+	}
+	if index >= length {
+		return 0, fmt.Errorf("index %d is out of range (length %d)", index, length)
+	}
+	return index, nil
+}
+
+// escapeTokens escapes reference tokens to join them back into a JSON Pointer
+func escapeTokens(tokens []string) []string {
+	escaped := make([]string, len(tokens))
+	for i, token := range tokens {
+		escaped[i] = escapeJSONPointer(token)
+	}
+	return escaped
+}
+
+// copyPointer copies the value a copy_keys JSON Pointer refers to from api_data to the
+// data. Values the API didn't return are left alone. The caller must hold the lock.
+func (obj *APIObject) copyPointer(ctx context.Context, pointer string) error {
+	value, err := GetObjectAtPointer(obj.apiData, pointer)
+	if err != nil {
+		tflog.Debug(ctx, "Not copying key missing from api_data", map[string]interface{}{"key": pointer, "error": err.Error()})
+		return nil
+	}
+	tflog.Debug(ctx, "Copying key from api_data to data", map[string]interface{}{"key": pointer, "new": value})
+	if obj.data == nil {
+		obj.data = map[string]interface{}{}
+	}
+	if err := SetObjectAtPointer(obj.data, pointer, value); err != nil {
+		return fmt.Errorf("failed to copy key '%s' to data: %w", pointer, err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const pointerDocument = `{
+	"id": "1",
+	"metadata": {"labels": {"app.kubernetes.io/name": "web", "a~b": "tilde"}},
+	"tags": ["red", "blue"],
+	"rules": [{"name": "allow"}, {"name": "deny"}]
+}`
+
+// TestGetObjectAtPointer tests resolving JSON Pointers
+func TestGetObjectAtPointer(t *testing.T) {
+	var data map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(pointerDocument), &data))
+
+	tests := map[string]struct {
+		pointer string
+		want    interface{}
+		wantErr string
+	}{
+		"field":          {pointer: "/id", want: "1"},
+		"escaped_slash":  {pointer: "/metadata/labels/app.kubernetes.io~1name", want: "web"},
+		"escaped_tilde":  {pointer: "/metadata/labels/a~0b", want: "tilde"},
+		"array_index":    {pointer: "/tags/1", want: "blue"},
+		"nested_array":   {pointer: "/rules/0/name", want: "allow"},
+		"missing":        {pointer: "/metadata/name", wantErr: "the object at '/metadata' does not have key 'name'"},
+		"out_of_range":   {pointer: "/tags/2", wantErr: "index 2 is out of range (length 2) in the array at '/tags'"},
+		"leading_zero":   {pointer: "/tags/01", wantErr: "'01' is not a valid array index"},
+		"end_of_array":   {pointer: "/tags/-", wantErr: "'-' is not a valid array index"},
+		"through_scalar": {pointer: "/id/x", wantErr: "the value at '/id' is not an object or array"},
+		"bad_escape":     {pointer: "/a~2b", wantErr: "'~' must be followed by '0' or '1'"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := GetObjectAtPointer(data, tc.pointer)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+// TestGetObjectAtKeyPointer tests that GetObjectAtKey takes both JSON Pointers and the legacy syntax
+func TestGetObjectAtKeyPointer(t *testing.T) {
+	ctx := context.Background()
+	var data map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(pointerDocument), &data))
+
+	v, err := GetStringAtKey(ctx, data, "/metadata/labels/app.kubernetes.io~1name")
+	require.NoError(t, err)
+	assert.Equal(t, "web", v)
+
+	v, err = GetStringAtKey(ctx, data, "rules/1/name")
+	require.NoError(t, err)
+	assert.Equal(t, "deny", v)
+
+	v, err = GetStringAtKey(ctx, data, "/rules/1/name")
+	require.NoError(t, err)
+	assert.Equal(t, "deny", v)
+}
+
+// TestSetObjectAtPointer tests setting values at JSON Pointers
+func TestSetObjectAtPointer(t *testing.T) {
+	data := map[string]interface{}{"tags": []interface{}{"red", "blue"}}
+
+	require.NoError(t, SetObjectAtPointer(data, "/metadata/a~1b", "x"))
+	require.NoError(t, SetObjectAtPointer(data, "/tags/0", "green"))
+	assert.Equal(t, map[string]interface{}{
+		"metadata": map[string]interface{}{"a/b": "x"},
+		"tags":     []interface{}{"green", "blue"},
+	}, data)
+
+	assert.ErrorContains(t, SetObjectAtPointer(data, "/tags/5", "x"), "out of range")
+	assert.ErrorContains(t, SetObjectAtPointer(data, "", "x"), "must refer to a field")
+}
+
+// TestJSONPointerKeys tests JSON Pointers in id_attribute and copy_keys
+func TestJSONPointerKeys(t *testing.T) {
+	client, err := NewAPIClient(&APIClientOpt{
+		URI:         "http://127.0.0.1:8083",
+		Timeout:     2,
+		IDAttribute: "/metadata/labels/app.kubernetes.io~1name",
+		CopyKeys:    []string{"/rules/1/name", "/metadata/labels/a~0b", "/missing"},
+	})
+	require.NoError(t, err)
+
+	obj, err := NewAPIObject(client, &APIObjectOpts{Path: "/api/objects", Data: `{"rules":[{"name":"a"},{"name":"b"}]}`})
+	require.NoError(t, err)
+	require.NoError(t, obj.updateInternalState(pointerDocument))
+
+	assert.Equal(t, "web", obj.ID)
+	assert.Equal(t, map[string]interface{}{
+		"rules":    []interface{}{map[string]interface{}{"name": "a"}, map[string]interface{}{"name": "deny"}},
+		"metadata": map[string]interface{}{"labels": map[string]interface{}{"a~b": "tilde"}},
+	}, obj.data)
+}
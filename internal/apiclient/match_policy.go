@@ -0,0 +1,44 @@
+package apiclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Match policies decide which object FindObjectWithOpts returns when several match
+const (
+	MatchPolicyFirst  = "first"  // The first matching object
+	MatchPolicyUnique = "unique" // The only matching object; several matches are an error
+	MatchPolicyLast   = "last"   // The last matching object
+)
+
+// MatchPolicies lists the valid match policies
+var MatchPolicies = []string{MatchPolicyFirst, MatchPolicyUnique, MatchPolicyLast}
+
+// errMultipleMatches is returned (wrapped) by FindObjectWithOpts when the match policy
+// is unique and several objects match
+var errMultipleMatches = errors.New("found several objects")
+
+// selectMatch picks the object to use from the objects matching the search
+func (obj *APIObject) selectMatch(ctx context.Context, search *SearchOpts, matches []map[string]interface{}) (map[string]interface{}, error) {
+	switch search.MatchPolicy {
+	case MatchPolicyLast:
+		return matches[len(matches)-1], nil
+	case MatchPolicyUnique:
+		if len(matches) == 1 {
+			return matches[0], nil
+		}
+		ids := make([]string, 0, len(matches))
+		for _, match := range matches {
+			id, err := obj.idFromData(ctx, match)
+			if err != nil || id == "" {
+				id = "<no id>"
+			}
+			ids = append(ids, fmt.Sprintf("'%s'", id))
+		}
+		return nil, fmt.Errorf("%w %s (ids %s) but match_policy is '%s'", errMultipleMatches, search.criteria(), strings.Join(ids, ", "), MatchPolicyUnique)
+	}
+	return matches[0], nil
+}
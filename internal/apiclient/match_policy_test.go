@@ -0,0 +1,82 @@
+package apiclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMatchPolicy tests which object is found when several match the search
+func TestMatchPolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(searchResults))
+	}))
+	defer server.Close()
+
+	client, err := NewAPIClient(&APIClientOpt{URI: server.URL, Timeout: 2, IDAttribute: "id"})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		policy  string
+		filter  string
+		wantID  string
+		wantErr string
+	}{
+		"default":      {wantID: "1"},
+		"first":        {policy: MatchPolicyFirst, wantID: "1"},
+		"last":         {policy: MatchPolicyLast, wantID: "2"},
+		"unique":       {policy: MatchPolicyUnique, wantErr: "found several objects with the 'name' key = 'web' (ids '1', '2') but match_policy is 'unique'"},
+		"unique_one":   {policy: MatchPolicyUnique, filter: "@.name == 'web' && @.env == 'dev'", wantID: "1"},
+		"unique_none":  {policy: MatchPolicyUnique, filter: "@.name == 'db'", wantErr: "failed to find an object"},
+		"last_filter":  {policy: MatchPolicyLast, filter: "@.env == 'prod'", wantID: "3"},
+		"invalid":      {policy: "any", wantErr: "match_policy 'any' is not valid"},
+		"unique_multi": {policy: MatchPolicyUnique, filter: "@.replicas > 0", wantErr: "(ids '1', '2', '3')"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			obj, err := NewAPIObject(client, &APIObjectOpts{Path: "/api/objects"})
+			require.NoError(t, err)
+
+			search := &SearchOpts{ResultsKey: "results", MatchPolicy: tc.policy, SearchFilter: tc.filter}
+			if tc.filter == "" {
+				search.SearchKey, search.SearchValue = "name", "web"
+			}
+			_, err = obj.FindObjectWithOpts(context.Background(), search)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantID, obj.ID)
+		})
+	}
+}
+
+// TestReadSearchMatchPolicy tests that a read_search matching several objects is an
+// error rather than the object being treated as deleted
+func TestReadSearchMatchPolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(searchResults))
+	}))
+	defer server.Close()
+
+	client, err := NewAPIClient(&APIClientOpt{URI: server.URL, Timeout: 2, IDAttribute: "id"})
+	require.NoError(t, err)
+
+	obj, err := NewAPIObject(client, &APIObjectOpts{
+		Path:       "/api/objects",
+		ID:         "1",
+		ReadSearch: map[string]string{"search_key": "name", "search_value": "web", "results_key": "results", "match_policy": MatchPolicyUnique},
+	})
+	require.NoError(t, err)
+	assert.ErrorContains(t, obj.ReadObject(context.Background()), "match_policy is 'unique'")
+	assert.Equal(t, "1", obj.ID)
+
+	_, err = NewAPIObject(client, &APIObjectOpts{Path: "/api/objects", ReadSearch: map[string]string{"search_key": "name", "search_value": "web", "match_policy": "any"}})
+	assert.ErrorContains(t, err, "match_policy 'any' is not valid")
+}
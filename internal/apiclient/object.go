@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
+	"slices"
 	"strings"
 	"sync"
 
@@ -15,8 +17,12 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
+// DefaultIDSeparator joins the parts of a composite id when no separator is configured
+const DefaultIDSeparator = ":"
+
 type APIObjectOpts struct {
 	Path          string
+	Endpoint      string
 	CreatePath    string
 	CreateMethod  string
 	ReadMethod    string
@@ -34,12 +40,46 @@ type APIObjectOpts struct {
 	ReadSearch    map[string]string
 	ID            string
 	IDAttribute   string
+	IDAttributes  []string // Paths of the values a composite id is made of, instead of IDAttribute
+	IDSeparator   string   // Separator between the parts of a composite id (defaults to DefaultIDSeparator)
 	Data          string
+
+	// APIResponse is the response last read from the API (as kept in state), so that
+	// path placeholders can refer to api_data before the object is read again
+	APIResponse string
+
+	// UpdateStrategy decides what is sent on update; see UpdateStrategies. Strategies
+	// other than full compare the data with the prior data set by SetPriorData.
+	UpdateStrategy string
+
+	// OnConflict decides what CreateObject does when the object already exists; see OnConflictModes
+	OnConflict string
+
+	// WaitForDeletion, when set, makes DeleteObject wait until the object is gone
+	WaitForDeletion *WaitForDeletion
+
+	// DestroyPolicy decides what DeleteObject does; see DestroyPolicies. The reset
+	// policy sends ResetData with ResetMethod, which defaults to the update method.
+	DestroyPolicy string
+	ResetData     string
+	ResetMethod   string
+
+	// ReadPatch is an RFC 6902 JSON Patch applied to every object read from the API, and
+	// WritePatch one applied to the data before it is sent to create or update the object
+	ReadPatch  string
+	WritePatch string
+
+	// ResponseEnvelopeKey is the path of the object in responses to create, read and
+	// update requests, and RequestEnvelopeKey the path the data is nested under when it
+	// is sent. They default to those of the client.
+	ResponseEnvelopeKey string
+	RequestEnvelopeKey  string
 }
 
 // APIObject is the state holding struct for a restapi_object resource
 type APIObject struct {
 	apiClient     *APIClient
+	endpoint      string
 	createMethod  string
 	createPath    string
 	readMethod    string
@@ -54,16 +94,33 @@ type APIObject struct {
 	readSearch    map[string]string
 	ID            string
 	IDAttribute   string
+	idAttributes  []string
+	idSeparator   string
+
+	updateStrategy string
+	onConflict     string
+	deletionWait   *WaitForDeletion
+	destroyPolicy  string
+
+	responseEnvelopeKey string
+	requestEnvelopeKey  string
+	requestEnvelope     []string // Keys the data is nested under when it is sent
 
 	// Set internally
-	mux         sync.RWMutex           // Protects data and apiData fields
-	data        map[string]interface{} // Data as managed by the user
-	readData    map[string]interface{} // Data to send during Read operation
-	updateData  map[string]interface{} // Data to send during Update operation
-	destroyData map[string]interface{} // Data to send during Destroy operation
-	apiData     map[string]interface{} // Data from the most recent read operation of the API object, as massaged to a map
-	apiResponse string                 // Raw API response from most recent read operation
-	searchPatch jsonpatch.Patch        // Pre-compiled JSON Patch for search_patch transformation
+	// The JSON documents below are usually objects, but can be arrays or values
+	mux         sync.RWMutex    // Protects data and apiData fields
+	data        interface{}     // Data as managed by the user
+	readData    interface{}     // Data to send during Read operation
+	updateData  interface{}     // Data to send during Update operation
+	destroyData interface{}     // Data to send during Destroy operation
+	resetData   interface{}     // Data to send to reset the object when destroying it
+	resetMethod string          // Method to send the reset data with
+	apiData     interface{}     // Data from the most recent read operation of the API object
+	priorData   interface{}     // Data as it was before the update, to compute update patches from
+	apiResponse string          // Raw API response from most recent read operation
+	searchPatch jsonpatch.Patch // Pre-compiled JSON Patch for search_patch transformation
+	readPatch   jsonpatch.Patch // Pre-compiled JSON Patch for objects read from the API
+	writePatch  jsonpatch.Patch // Pre-compiled JSON Patch for data sent to the API
 }
 
 // NewAPIObject makes an APIobject to manage a RESTful object in an API
@@ -91,6 +148,9 @@ func NewAPIObject(iClient *APIClient, opts *APIObjectOpts) (*APIObject, error) {
 	if opts.UpdateMethod == "" {
 		opts.UpdateMethod = iClient.updateMethod
 	}
+	if opts.ResetMethod == "" {
+		opts.ResetMethod = opts.UpdateMethod
+	}
 	if opts.UpdateData == "" {
 		opts.UpdateData = iClient.updateData
 	}
@@ -100,6 +160,12 @@ func NewAPIObject(iClient *APIClient, opts *APIObjectOpts) (*APIObject, error) {
 	if opts.DestroyData == "" {
 		opts.DestroyData = iClient.destroyData
 	}
+	if opts.ResponseEnvelopeKey == "" {
+		opts.ResponseEnvelopeKey = iClient.responseEnvelopeKey
+	}
+	if opts.RequestEnvelopeKey == "" {
+		opts.RequestEnvelopeKey = iClient.requestEnvelopeKey
+	}
 	if opts.CreatePath == "" {
 		opts.CreatePath = opts.Path
 	}
@@ -116,58 +182,138 @@ func NewAPIObject(iClient *APIClient, opts *APIObjectOpts) (*APIObject, error) {
 		opts.SearchPath = opts.Path
 	}
 
+	if opts.IDSeparator == "" {
+		opts.IDSeparator = DefaultIDSeparator
+	}
+
+	if opts.UpdateStrategy != "" && !slices.Contains(UpdateStrategies, opts.UpdateStrategy) {
+		return nil, fmt.Errorf("update_strategy '%s' is not valid, must be one of %s", opts.UpdateStrategy, strings.Join(UpdateStrategies, ", "))
+	}
+
+	if opts.OnConflict != "" && !slices.Contains(OnConflictModes, opts.OnConflict) {
+		return nil, fmt.Errorf("on_conflict '%s' is not valid, must be one of %s", opts.OnConflict, strings.Join(OnConflictModes, ", "))
+	}
+	hasReadSearch := opts.ReadSearch["search_filter"] != "" || (opts.ReadSearch["search_key"] != "" && opts.ReadSearch["search_value"] != "")
+	if opts.OnConflict != "" && opts.OnConflict != OnConflictError && !hasReadSearch {
+		return nil, fmt.Errorf("on_conflict '%s' requires read_search with search_filter or search_key and search_value to find the existing object", opts.OnConflict)
+	}
+	if opts.ReadSearch["search_filter"] != "" || opts.ReadSearch["match_policy"] != "" {
+		search := SearchOpts{
+			SearchKey:    opts.ReadSearch["search_key"],
+			SearchValue:  opts.ReadSearch["search_value"],
+			SearchFilter: opts.ReadSearch["search_filter"],
+			MatchPolicy:  opts.ReadSearch["match_policy"],
+		}
+		if _, err := search.compile(); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.WaitForDeletion != nil {
+		wait := *opts.WaitForDeletion
+		if (wait.StateKey == "") != (wait.StateValue == "") {
+			return nil, fmt.Errorf("wait_for_deletion state_key and state_value must be set together")
+		}
+		if wait.Interval <= 0 {
+			wait.Interval = DefaultDeletionPollInterval
+		}
+		opts.WaitForDeletion = &wait
+	}
+
+	if opts.DestroyPolicy != "" && !slices.Contains(DestroyPolicies, opts.DestroyPolicy) {
+		return nil, fmt.Errorf("destroy_policy '%s' is not valid, must be one of %s", opts.DestroyPolicy, strings.Join(DestroyPolicies, ", "))
+	}
+	if opts.DestroyPolicy == DestroyPolicyReset && opts.ResetData == "" {
+		return nil, fmt.Errorf("destroy_policy '%s' requires reset_data", DestroyPolicyReset)
+	}
+
+	if _, err := envelopeKeys("response_envelope_key", opts.ResponseEnvelopeKey); err != nil {
+		return nil, err
+	}
+	requestEnvelope, err := envelopeKeys("request_envelope_key", opts.RequestEnvelopeKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Endpoint != "" && !iClient.HasEndpoint(opts.Endpoint) {
+		return nil, fmt.Errorf("endpoint '%s' is not configured on the provider", opts.Endpoint)
+	}
+
 	obj := APIObject{
-		apiClient:     iClient,
-		readPath:      opts.ReadPath,
-		createPath:    opts.CreatePath,
-		updatePath:    opts.UpdatePath,
-		createMethod:  opts.CreateMethod,
-		readMethod:    opts.ReadMethod,
-		updateMethod:  opts.UpdateMethod,
-		destroyMethod: opts.DestroyMethod,
-		deletePath:    opts.DestroyPath,
-		searchPath:    opts.SearchPath,
-		queryString:   opts.QueryString,
-		debug:         opts.Debug,
-		readSearch:    opts.ReadSearch,
-		ID:            opts.ID,
-		IDAttribute:   opts.IDAttribute,
-		data:          make(map[string]interface{}),
-		readData:      nil,
-		updateData:    nil,
-		destroyData:   nil,
-		apiData:       make(map[string]interface{}),
+		apiClient:      iClient,
+		endpoint:       opts.Endpoint,
+		readPath:       opts.ReadPath,
+		createPath:     opts.CreatePath,
+		updatePath:     opts.UpdatePath,
+		createMethod:   opts.CreateMethod,
+		readMethod:     opts.ReadMethod,
+		updateMethod:   opts.UpdateMethod,
+		resetMethod:    opts.ResetMethod,
+		destroyMethod:  opts.DestroyMethod,
+		deletePath:     opts.DestroyPath,
+		searchPath:     opts.SearchPath,
+		queryString:    opts.QueryString,
+		debug:          opts.Debug,
+		readSearch:     opts.ReadSearch,
+		ID:             opts.ID,
+		IDAttribute:    opts.IDAttribute,
+		idAttributes:   opts.IDAttributes,
+		idSeparator:    opts.IDSeparator,
+		updateStrategy: opts.UpdateStrategy,
+		onConflict:     opts.OnConflict,
+		deletionWait:   opts.WaitForDeletion,
+		destroyPolicy:  opts.DestroyPolicy,
+
+		responseEnvelopeKey: opts.ResponseEnvelopeKey,
+		requestEnvelopeKey:  opts.RequestEnvelopeKey,
+		requestEnvelope:     requestEnvelope,
+
+		data:        make(map[string]interface{}),
+		readData:    nil,
+		updateData:  nil,
+		destroyData: nil,
+		apiData:     make(map[string]interface{}),
 	}
 
 	if opts.Data != "" {
 		tflog.Debug(ctx, "Parsing data", map[string]interface{}{"data": opts.Data})
 
-		err := json.Unmarshal([]byte(opts.Data), &obj.data)
+		err := DecodeJSON([]byte(opts.Data), &obj.data)
 		if err != nil {
 			return &obj, fmt.Errorf("error parsing data provided: %v", err.Error())
 		}
 
+		// An array or value has no id_attribute to find the id at, in the data or the API's responses
+		if _, isObject := obj.data.(map[string]interface{}); !isObject && obj.ID == "" {
+			return &obj, fmt.Errorf("provided data is not a JSON object, so the object's id cannot be read from its %s attribute; the id must be set explicitly", obj.idAttributeNames())
+		}
+
 		// Opportunistically extract ID from provided data if present.
 		// If not present, we'll attempt to get it later from the API response
 		// (when write_returns_object or create_returns_object is true) or from search.
 		if obj.ID == "" {
-			var tmp string
-			tmp, err := GetStringAtKey(ctx, obj.data, obj.IDAttribute)
+			tmp, err := obj.idFromData(ctx, obj.data)
 			if err == nil {
 				tflog.Debug(ctx, "opportunisticly set id from data provided", map[string]interface{}{"id": tmp})
 				obj.ID = tmp
 			} else if !obj.apiClient.writeReturnsObject && !obj.apiClient.createReturnsObject && obj.searchPath == "" {
 				// If the id is not set and we cannot obtain it
 				// later, error out to be safe
-				return &obj, fmt.Errorf("provided data does not have %s attribute for the object's id and the client is not configured to read the object from a POST response; without an id, the object cannot be managed", obj.IDAttribute)
+				return &obj, fmt.Errorf("provided data does not have %s attribute for the object's id and the client is not configured to read the object from a POST response; without an id, the object cannot be managed", obj.idAttributeNames())
 			}
 		}
 	}
 
+	if opts.APIResponse != "" {
+		if err := DecodeJSON([]byte(opts.APIResponse), &obj.apiData); err != nil {
+			tflog.Warn(ctx, "Could not parse the prior API response, api_data will not be available to path placeholders", map[string]interface{}{"error": err})
+		}
+	}
+
 	if opts.ReadData != "" {
 		tflog.Debug(ctx, "Parsing read data", map[string]interface{}{"readData": opts.ReadData})
 
-		err := json.Unmarshal([]byte(opts.ReadData), &obj.readData)
+		err := DecodeJSON([]byte(opts.ReadData), &obj.readData)
 		if err != nil {
 			return &obj, fmt.Errorf("error parsing read data provided: %v", err.Error())
 		}
@@ -176,7 +322,7 @@ func NewAPIObject(iClient *APIClient, opts *APIObjectOpts) (*APIObject, error) {
 	if opts.UpdateData != "" {
 		tflog.Debug(ctx, "Parsing update data", map[string]interface{}{"updateData": opts.UpdateData})
 
-		err := json.Unmarshal([]byte(opts.UpdateData), &obj.updateData)
+		err := DecodeJSON([]byte(opts.UpdateData), &obj.updateData)
 		if err != nil {
 			return &obj, fmt.Errorf("error parsing update data provided: %v", err.Error())
 		}
@@ -185,19 +331,29 @@ func NewAPIObject(iClient *APIClient, opts *APIObjectOpts) (*APIObject, error) {
 	if opts.DestroyData != "" {
 		tflog.Debug(ctx, "Parsing destroy data", map[string]interface{}{"destroyData": opts.DestroyData})
 
-		err := json.Unmarshal([]byte(opts.DestroyData), &obj.destroyData)
+		err := DecodeJSON([]byte(opts.DestroyData), &obj.destroyData)
 		if err != nil {
 			return &obj, fmt.Errorf("error parsing destroy data provided: %v", err.Error())
 		}
 	}
 
-	if searchPatchStr := opts.ReadSearch["search_patch"]; searchPatchStr != "" {
-		tflog.Debug(ctx, "Compiling search_patch", map[string]interface{}{"search_patch": searchPatchStr})
-		patch, err := jsonpatch.DecodePatch([]byte(searchPatchStr))
+	if opts.ResetData != "" {
+		tflog.Debug(ctx, "Parsing reset data", map[string]interface{}{"resetData": opts.ResetData})
+
+		err := DecodeJSON([]byte(opts.ResetData), &obj.resetData)
 		if err != nil {
-			return &obj, fmt.Errorf("failed to compile search_patch: %w", err)
+			return &obj, fmt.Errorf("error parsing reset data provided: %v", err.Error())
 		}
-		obj.searchPatch = patch
+	}
+
+	if obj.searchPatch, err = compilePatch(ctx, "search_patch", opts.ReadSearch["search_patch"]); err != nil {
+		return &obj, err
+	}
+	if obj.readPatch, err = compilePatch(ctx, "read_patch", opts.ReadPatch); err != nil {
+		return &obj, err
+	}
+	if obj.writePatch, err = compilePatch(ctx, "write_patch", opts.WritePatch); err != nil {
+		return &obj, err
 	}
 
 	tflog.Debug(ctx, "Constructed object", map[string]interface{}{"object": obj.String()})
@@ -213,6 +369,10 @@ func (obj *APIObject) String() string {
 
 	var buffer bytes.Buffer
 	buffer.WriteString(fmt.Sprintf("id: %s\n", obj.ID))
+	if len(obj.idAttributes) > 0 {
+		buffer.WriteString(fmt.Sprintf("id_attributes: %s\n", strings.Join(obj.idAttributes, ", ")))
+		buffer.WriteString(fmt.Sprintf("id_separator: %s\n", obj.idSeparator))
+	}
 	buffer.WriteString(fmt.Sprintf("get_path: %s\n", obj.readPath))
 	buffer.WriteString(fmt.Sprintf("post_path: %s\n", obj.createPath))
 	buffer.WriteString(fmt.Sprintf("put_path: %s\n", obj.updatePath))
@@ -221,17 +381,40 @@ func (obj *APIObject) String() string {
 	buffer.WriteString(fmt.Sprintf("create_method: %s\n", obj.createMethod))
 	buffer.WriteString(fmt.Sprintf("read_method: %s\n", obj.readMethod))
 	buffer.WriteString(fmt.Sprintf("update_method: %s\n", obj.updateMethod))
+	buffer.WriteString(fmt.Sprintf("update_strategy: %s\n", obj.updateStrategy))
+	buffer.WriteString(fmt.Sprintf("on_conflict: %s\n", obj.onConflict))
+	if obj.deletionWait != nil {
+		buffer.WriteString(fmt.Sprintf("wait_for_deletion: %+v\n", *obj.deletionWait))
+	}
 	buffer.WriteString(fmt.Sprintf("destroy_method: %s\n", obj.destroyMethod))
+	buffer.WriteString(fmt.Sprintf("destroy_policy: %s\n", obj.destroyPolicy))
+	buffer.WriteString(fmt.Sprintf("response_envelope_key: %s\n", obj.responseEnvelopeKey))
+	buffer.WriteString(fmt.Sprintf("request_envelope_key: %s\n", obj.requestEnvelopeKey))
 	buffer.WriteString(fmt.Sprintf("debug: %t\n", obj.debug))
+	buffer.WriteString(fmt.Sprintf("endpoint: %s\n", obj.endpoint))
 	buffer.WriteString(fmt.Sprintf("read_search: %s\n", spew.Sdump(obj.readSearch)))
 	buffer.WriteString(fmt.Sprintf("data: %s\n", spew.Sdump(obj.data)))
 	buffer.WriteString(fmt.Sprintf("read_data: %s\n", spew.Sdump(obj.readData)))
 	buffer.WriteString(fmt.Sprintf("update_data: %s\n", spew.Sdump(obj.updateData)))
 	buffer.WriteString(fmt.Sprintf("destroy_data: %s\n", spew.Sdump(obj.destroyData)))
+	buffer.WriteString(fmt.Sprintf("reset_data: %s\n", spew.Sdump(obj.resetData)))
 	buffer.WriteString(fmt.Sprintf("api_data: %s\n", spew.Sdump(obj.apiData)))
 	return buffer.String()
 }
 
+// SetPriorData sets the data as it was before the planned changes, which update
+// strategies other than full compare against to work out what to send
+func (obj *APIObject) SetPriorData(data string) error {
+	var prior interface{}
+	if err := DecodeJSON([]byte(data), &prior); err != nil {
+		return fmt.Errorf("error parsing prior data: %v", err.Error())
+	}
+	obj.mux.Lock()
+	defer obj.mux.Unlock()
+	obj.priorData = prior
+	return nil
+}
+
 // SetDataFromMap sets the object's internal state from a map
 // This allows more fine-grained manipulation of an object's data, outside of reads to the API
 func (obj *APIObject) SetDataFromMap(d map[string]interface{}) error {
@@ -251,17 +434,26 @@ func (obj *APIObject) updateInternalState(state string) error {
 	obj.mux.Lock()
 	defer obj.mux.Unlock()
 
-	err := json.Unmarshal([]byte(state), &obj.apiData)
+	state, err := obj.patchResponse(state)
+	if err != nil {
+		return err
+	}
+
+	// Unmarshal into a new value, as unmarshaling into the existing map would keep
+	// keys the API no longer returns
+	var apiData interface{}
+	err = DecodeJSON([]byte(state), &apiData)
 	if err != nil {
 		return err
 	}
+	obj.apiData = apiData
 
 	obj.apiResponse = state
 
 	// A usable ID was not passed (in constructor or here),
 	// so we have to guess what it is from the data structure
 	if obj.ID == "" {
-		val, err := GetStringAtKey(ctx, obj.apiData, obj.IDAttribute)
+		val, err := obj.idFromData(ctx, obj.apiData)
 		if err != nil {
 			return fmt.Errorf("error extracting ID from data element: %s", err)
 		}
@@ -275,8 +467,20 @@ func (obj *APIObject) updateInternalState(state string) error {
 	// that need to be included in subsequent requests.
 	if len(obj.apiClient.copyKeys) > 0 {
 		for _, key := range obj.apiClient.copyKeys {
-			tflog.Debug(ctx, "Copying key from api_data to data\n", map[string]interface{}{"key": key, "new": obj.apiData[key], "old": obj.data[key]})
-			obj.data[key] = obj.apiData[key]
+			if IsJSONPointer(key) {
+				if err := obj.copyPointer(ctx, key); err != nil {
+					return err
+				}
+				continue
+			}
+			data, dataIsObject := obj.data.(map[string]interface{})
+			apiData, apiDataIsObject := obj.apiData.(map[string]interface{})
+			if !dataIsObject || !apiDataIsObject {
+				tflog.Debug(ctx, "Not copying key, as data or api_data is not a JSON object\n", map[string]interface{}{"key": key})
+				continue
+			}
+			tflog.Debug(ctx, "Copying key from api_data to data\n", map[string]interface{}{"key": key, "new": apiData[key], "old": data[key]})
+			data[key] = apiData[key]
 		}
 	} else {
 		tflog.Debug(ctx, "copy_keys is empty - not attempting to copy data", nil)
@@ -285,7 +489,38 @@ func (obj *APIObject) updateInternalState(state string) error {
 	return err
 }
 
-func (obj *APIObject) CreateObject(ctx context.Context) error {
+// setResponse updates the internal state from the response to a create, read or update
+// request, which is in the response envelope if there is one
+func (obj *APIObject) setResponse(ctx context.Context, response string) error {
+	response, err := obj.unwrapResponse(ctx, response)
+	if err != nil {
+		return err
+	}
+	return obj.updateInternalState(response)
+}
+
+// sendRequest sends a request for the object to its endpoint, expanding the placeholders
+// in path and tagging it with the path template it was built from
+func (obj *APIObject) sendRequest(ctx context.Context, template string, method string, path string, data string) (string, int, error) {
+	path, err := obj.expandPath(ctx, path)
+	if err != nil {
+		return "", 0, err
+	}
+	return obj.apiClient.SendEndpointRequest(withPathTemplate(ctx, template), obj.endpoint, method, path, data, obj.debug)
+}
+
+func (obj *APIObject) CreateObject(ctx context.Context) (err error) {
+	ctx, span := obj.apiClient.telemetry.startObjectSpan(ctx, "CreateObject", obj)
+	defer func() { endSpan(span, err) }()
+
+	// Look for an existing object to take over before creating a new one
+	adopting := obj.onConflict == OnConflictAdopt || obj.onConflict == OnConflictAdoptAndUpdate
+	if adopting {
+		if adopted, err := obj.adoptExisting(ctx); adopted || err != nil {
+			return err
+		}
+	}
+
 	// Failsafe: The constructor should prevent this situation, but
 	// protect here also. If no id is set, and the API does not respond
 	// with the id of whatever gets created, we have no way to know what
@@ -295,8 +530,11 @@ func (obj *APIObject) CreateObject(ctx context.Context) error {
 	}
 
 	obj.mux.RLock()
-	b, _ := json.Marshal(obj.data)
+	b, err := obj.writePayload(obj.data)
 	obj.mux.RUnlock()
+	if err != nil {
+		return err
+	}
 
 	postPath := obj.createPath
 	if obj.queryString != "" {
@@ -304,8 +542,15 @@ func (obj *APIObject) CreateObject(ctx context.Context) error {
 		postPath = fmt.Sprintf("%s?%s", obj.createPath, obj.queryString)
 	}
 
-	resultString, _, err := obj.apiClient.SendRequest(ctx, obj.createMethod, strings.Replace(postPath, "{id}", obj.ID, -1), string(b), obj.debug)
+	resultString, code, err := obj.sendRequest(ctx, obj.createPath, obj.createMethod, postPath, string(b))
 	if err != nil {
+		// The object may have been created since it was looked for, such as by a concurrent run
+		if adopting && code == http.StatusConflict {
+			tflog.Info(ctx, "Create returned a conflict, looking for the existing object", map[string]interface{}{"on_conflict": obj.onConflict})
+			if adopted, adoptErr := obj.adoptExisting(ctx); adopted || adoptErr != nil {
+				return adoptErr
+			}
+		}
 		return err
 	}
 
@@ -315,7 +560,7 @@ func (obj *APIObject) CreateObject(ctx context.Context) error {
 			"write_returns_object":  obj.apiClient.writeReturnsObject,
 			"create_returns_object": obj.apiClient.createReturnsObject,
 		})
-		err = obj.updateInternalState(resultString)
+		err = obj.setResponse(ctx, resultString)
 		// Yet another failsafe. In case something terrible went wrong internally,
 		// bail out so the user at least knows that the ID did not get set.
 		if obj.ID == "" {
@@ -331,66 +576,88 @@ func (obj *APIObject) CreateObject(ctx context.Context) error {
 	return err
 }
 
-func (obj *APIObject) ReadObject(ctx context.Context) error {
+// hasReadSearch reports whether read_search is configured to find the object
+func (obj *APIObject) hasReadSearch() bool {
+	return obj.readSearch["search_filter"] != "" || (obj.readSearch["search_key"] != "" && obj.readSearch["search_value"] != "")
+}
+
+// findByReadSearch finds the object with FindObjectWithOpts using the read_search settings
+func (obj *APIObject) findByReadSearch(ctx context.Context) (map[string]interface{}, error) {
+	// Support {id} placeholder substitution in search_value and search_filter
+	search := &SearchOpts{
+		SearchKey:    obj.readSearch["search_key"],
+		SearchValue:  strings.ReplaceAll(obj.readSearch["search_value"], "{id}", obj.ID),
+		SearchFilter: strings.ReplaceAll(obj.readSearch["search_filter"], "{id}", escapeFilterString(obj.ID)),
+		ResultsKey:   obj.readSearch["results_key"],
+		MatchPolicy:  obj.readSearch["match_policy"],
+	}
+
+	// Ensure searchPath is set correctly. If not explicitly set, derive it from readPath
+	// by removing the /{id} suffix to get the collection endpoint.
+	if obj.searchPath == "" {
+		obj.searchPath = strings.TrimSuffix(obj.readPath, "/{id}")
+	}
+
+	search.QueryString = obj.readSearch["query_string"]
+	// Merge object-level query string with search-specific query string
+	if obj.queryString != "" {
+		tflog.Debug(ctx, "Adding object-level query string to search", map[string]interface{}{"object_query_string": obj.queryString})
+		if search.QueryString != "" {
+			search.QueryString = fmt.Sprintf("%s&%s", search.QueryString, obj.queryString)
+		} else {
+			search.QueryString = obj.queryString
+		}
+	}
+
+	if len(obj.readSearch["search_data"]) > 0 {
+		tmpData, _ := json.Marshal(obj.readSearch["search_data"])
+		search.SearchData = string(tmpData)
+		tflog.Debug(ctx, "Using search data", map[string]interface{}{"search_data": search.SearchData})
+	}
+
+	return obj.FindObjectWithOpts(ctx, search)
+}
+
+// setSearchResult updates the object's state from an object found by read_search,
+// applying search_patch if configured
+func (obj *APIObject) setSearchResult(ctx context.Context, objFound map[string]interface{}) error {
+	if obj.searchPatch != nil {
+		tflog.Debug(ctx, "Applying search_patch transformation")
+		patchedObj, err := ApplyJSONPatch(ctx, objFound, obj.searchPatch)
+		if err != nil {
+			return fmt.Errorf("failed to apply search_patch: %w", err)
+		}
+		objFound = patchedObj
+		tflog.Debug(ctx, "Successfully applied search_patch")
+	}
+
+	objFoundString, _ := json.Marshal(objFound)
+	return obj.updateInternalState(string(objFoundString))
+}
+
+func (obj *APIObject) ReadObject(ctx context.Context) (err error) {
+	ctx, span := obj.apiClient.telemetry.startObjectSpan(ctx, "ReadObject", obj)
+	defer func() { endSpan(span, err) }()
+
 	if obj.ID == "" {
 		return fmt.Errorf("cannot read an object unless the ID has been set")
 	}
 
 	// If read_search is configured, use FindObject to locate the resource by search criteria
 	// instead of using the ID directly. This handles APIs that require searching for objects.
-	searchKey := obj.readSearch["search_key"]
-	searchValue := obj.readSearch["search_value"]
-
-	// Support {id} placeholder substitution in search_value
-	searchValue = strings.ReplaceAll(searchValue, "{id}", obj.ID)
-
-	if searchKey != "" && searchValue != "" {
-		// Ensure searchPath is set correctly. If not explicitly set, derive it from readPath
-		// by removing the /{id} suffix to get the collection endpoint.
-		if obj.searchPath == "" {
-			obj.searchPath = strings.TrimSuffix(obj.readPath, "/{id}")
-		}
-
-		queryString := obj.readSearch["query_string"]
-		// Merge object-level query string with search-specific query string
-		if obj.queryString != "" {
-			tflog.Debug(ctx, "Adding object-level query string to search", map[string]interface{}{"object_query_string": obj.queryString})
-			if queryString != "" {
-				queryString = fmt.Sprintf("%s&%s", queryString, obj.queryString)
-			} else {
-				queryString = obj.queryString
-			}
-		}
-
-		searchData := ""
-		if len(obj.readSearch["search_data"]) > 0 {
-			tmpData, _ := json.Marshal(obj.readSearch["search_data"])
-			searchData = string(tmpData)
-			tflog.Debug(ctx, "Using search data", map[string]interface{}{"search_data": searchData})
+	if obj.hasReadSearch() {
+		objFound, err := obj.findByReadSearch(ctx)
+		// Picking one of several objects would be a guess, so don't treat that as deleted
+		if errors.Is(err, errMultipleMatches) {
+			return err
 		}
-
-		resultsKey := obj.readSearch["results_key"]
-		objFound, err := obj.FindObject(ctx, queryString, searchKey, searchValue, resultsKey, searchData)
 		if err != nil || objFound == nil {
 			// Object not found in search results - treat as deleted, remove from state
-			tflog.Info(ctx, "Search did not find object", map[string]interface{}{"search_key": searchKey, "search_value": searchValue})
+			tflog.Info(ctx, "Search did not find object", map[string]interface{}{"search_key": obj.readSearch["search_key"], "search_value": obj.readSearch["search_value"], "search_filter": obj.readSearch["search_filter"]})
 			obj.ID = ""
 			return nil
 		}
-
-		// Apply search_patch if configured
-		if obj.searchPatch != nil {
-			tflog.Debug(ctx, "Applying search_patch transformation")
-			patchedObj, err := ApplyJSONPatch(ctx, objFound, obj.searchPatch)
-			if err != nil {
-				return fmt.Errorf("failed to apply search_patch: %w", err)
-			}
-			objFound = patchedObj
-			tflog.Debug(ctx, "Successfully applied search_patch")
-		}
-
-		objFoundString, _ := json.Marshal(objFound)
-		return obj.updateInternalState(string(objFoundString))
+		return obj.setSearchResult(ctx, objFound)
 	}
 
 	// Normal read path (no search configured)
@@ -407,7 +674,7 @@ func (obj *APIObject) ReadObject(ctx context.Context) error {
 		tflog.Debug(ctx, "Using read data", map[string]interface{}{"read_data": send})
 	}
 
-	resultString, _, err := obj.apiClient.SendRequest(ctx, obj.readMethod, strings.Replace(getPath, "{id}", obj.ID, -1), send, obj.debug)
+	resultString, _, err := obj.sendRequest(ctx, obj.readPath, obj.readMethod, getPath, send)
 	if err != nil {
 		// 404 during refresh means the object was deleted outside Terraform.
 		// Clear the ID to remove it from state gracefully.
@@ -419,27 +686,41 @@ func (obj *APIObject) ReadObject(ctx context.Context) error {
 		return err
 	}
 
-	return obj.updateInternalState(resultString)
+	return obj.setResponse(ctx, resultString)
 }
 
-func (obj *APIObject) UpdateObject(ctx context.Context) error {
+func (obj *APIObject) UpdateObject(ctx context.Context) (err error) {
+	ctx, span := obj.apiClient.telemetry.startObjectSpan(ctx, "UpdateObject", obj)
+	defer func() { endSpan(span, err) }()
+
 	if obj.ID == "" {
 		return fmt.Errorf("cannot update an object unless the ID has been set")
 	}
 
 	send := ""
+	contentType := ""
+	changed := true
 	// If update_data is configured, use it for the update payload.
-	// Otherwise, use the full managed data. This allows for partial updates.
+	// Otherwise, use the managed data as the update strategy says. This allows for partial updates.
 	obj.mux.RLock()
 	if obj.updateData != nil {
 		updateData, _ := json.Marshal(obj.updateData)
 		send = string(updateData)
 		tflog.Debug(ctx, "Using update data", map[string]interface{}{"update_data": send})
 	} else {
-		b, _ := json.Marshal(obj.data)
-		send = string(b)
+		send, contentType, changed, err = obj.updatePayload()
+		tflog.Debug(ctx, "Using update strategy", map[string]interface{}{"update_strategy": obj.updateStrategy, "payload": send})
 	}
 	obj.mux.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	// A patch with nothing in it would be a no-op, so just refresh the object
+	if !changed {
+		tflog.Debug(ctx, "Data is unchanged, skipping update request", map[string]interface{}{"update_strategy": obj.updateStrategy})
+		return obj.ReadObject(ctx)
+	}
 
 	putPath := obj.updatePath
 	if obj.queryString != "" {
@@ -447,14 +728,14 @@ func (obj *APIObject) UpdateObject(ctx context.Context) error {
 		putPath = fmt.Sprintf("%s?%s", obj.updatePath, obj.queryString)
 	}
 
-	resultString, _, err := obj.apiClient.SendRequest(ctx, obj.updateMethod, strings.Replace(putPath, "{id}", obj.ID, -1), send, obj.debug)
+	resultString, _, err := obj.sendRequest(withContentType(ctx, contentType), obj.updatePath, obj.updateMethod, putPath, send)
 	if err != nil {
 		return err
 	}
 
 	if obj.apiClient.writeReturnsObject {
 		tflog.Debug(ctx, "Parsing response from PUT to update internal structures", map[string]interface{}{"write_returns_object": obj.apiClient.writeReturnsObject})
-		err = obj.updateInternalState(resultString)
+		err = obj.setResponse(ctx, resultString)
 	} else {
 		tflog.Debug(ctx, "Requesting updated object from API", map[string]interface{}{"write_returns_object": obj.apiClient.writeReturnsObject})
 		err = obj.ReadObject(ctx)
@@ -462,12 +743,25 @@ func (obj *APIObject) UpdateObject(ctx context.Context) error {
 	return err
 }
 
-func (obj *APIObject) DeleteObject(ctx context.Context) error {
+func (obj *APIObject) DeleteObject(ctx context.Context) (err error) {
+	ctx, span := obj.apiClient.telemetry.startObjectSpan(ctx, "DeleteObject", obj)
+	defer func() { endSpan(span, err) }()
+
 	if obj.ID == "" {
 		tflog.Warn(ctx, "Attempting to delete an object that has no id set. Assuming this is OK.", map[string]interface{}{})
 		return nil
 	}
 
+	switch obj.destroyPolicy {
+	case DestroyPolicyAbandon:
+		tflog.Info(ctx, "Abandoning object instead of deleting it", map[string]interface{}{"id": obj.ID, "destroy_policy": obj.destroyPolicy})
+		return nil
+	case DestroyPolicyProtect:
+		return fmt.Errorf("the object with id '%s' is protected by destroy_policy '%s'; change destroy_policy to destroy it", obj.ID, obj.destroyPolicy)
+	case DestroyPolicyReset:
+		return obj.resetObject(ctx)
+	}
+
 	deletePath := obj.deletePath
 	if obj.queryString != "" {
 		tflog.Debug(ctx, "Adding query string", map[string]interface{}{"query_string": obj.queryString})
@@ -481,33 +775,58 @@ func (obj *APIObject) DeleteObject(ctx context.Context) error {
 		tflog.Debug(ctx, "Using destroy data", map[string]interface{}{"destroy_data": string(destroyData)})
 	}
 
-	_, code, err := obj.apiClient.SendRequest(ctx, obj.destroyMethod, strings.Replace(deletePath, "{id}", obj.ID, -1), send, obj.debug)
+	_, code, err := obj.sendRequest(ctx, obj.deletePath, obj.destroyMethod, deletePath, send)
 	if err != nil {
 		// 404 (Not Found) or 410 (Gone) during delete is acceptable -
 		// the object is already gone, which is the desired end state.
 		if code == http.StatusNotFound || code == http.StatusGone {
 			tflog.Warn(ctx, "404/410 error while deleting object. Assuming already deleted.", map[string]interface{}{"id": obj.ID, "path": obj.deletePath})
-			err = nil
+			return nil
 		}
+		return err
 	}
 
-	return err
+	// The API may only have started deleting the object
+	if obj.deletionWait != nil {
+		return obj.waitForDeletion(ctx)
+	}
+	return nil
 }
 
-func (obj *APIObject) FindObject(ctx context.Context, queryString string, searchKey string, searchValue string, resultsKey string, searchData string) (map[string]interface{}, error) {
-	var objFound map[string]interface{}
+func (obj *APIObject) FindObject(ctx context.Context, queryString string, searchKey string, searchValue string, resultsKey string, searchData string) (objFound map[string]interface{}, err error) {
+	return obj.FindObjectWithOpts(ctx, &SearchOpts{
+		QueryString: queryString,
+		SearchKey:   searchKey,
+		SearchValue: searchValue,
+		ResultsKey:  resultsKey,
+		SearchData:  searchData,
+	})
+}
+
+// FindObjectWithOpts searches the objects at the search path for the one matching the
+// search criteria, and sets the object's ID to its id
+func (obj *APIObject) FindObjectWithOpts(ctx context.Context, search *SearchOpts) (objFound map[string]interface{}, err error) {
+	ctx, span := obj.apiClient.telemetry.startObjectSpan(ctx, "FindObject", obj)
+	defer func() { endSpan(span, err) }()
+
 	var dataArray []interface{}
 	var ok bool
 
+	filter, err := search.compile()
+	if err != nil {
+		return nil, err
+	}
+	resultsKey := search.ResultsKey
+
 	// Issue a GET to the base path and expect results to come back
 	searchPath := obj.searchPath
-	if queryString != "" {
-		tflog.Debug(ctx, "Adding query string", map[string]interface{}{"query_string": queryString})
-		searchPath = fmt.Sprintf("%s?%s", obj.searchPath, queryString)
+	if search.QueryString != "" {
+		tflog.Debug(ctx, "Adding query string", map[string]interface{}{"query_string": search.QueryString})
+		searchPath = fmt.Sprintf("%s?%s", obj.searchPath, search.QueryString)
 	}
 
 	tflog.Debug(ctx, "Calling API on path", map[string]interface{}{"path": searchPath})
-	resultString, _, err := obj.apiClient.SendRequest(ctx, obj.apiClient.readMethod, searchPath, searchData, obj.debug)
+	resultString, _, err := obj.sendRequest(ctx, obj.searchPath, obj.apiClient.readMethod, searchPath, search.SearchData)
 	if err != nil {
 		return nil, err
 	}
@@ -515,7 +834,7 @@ func (obj *APIObject) FindObject(ctx context.Context, queryString string, search
 	// Parse it seeking JSON data
 	tflog.Debug(ctx, "Response received... parsing", nil)
 	var result interface{}
-	err = json.Unmarshal([]byte(resultString), &result)
+	err = DecodeJSON([]byte(resultString), &result)
 	if err != nil {
 		return nil, err
 	}
@@ -545,7 +864,14 @@ func (obj *APIObject) FindObject(ctx context.Context, queryString string, search
 		}
 	}
 
-	// Loop through all of the results seeking the specific record
+	// A filter selects the matching records itself
+	if filter != nil {
+		tflog.Debug(ctx, "Filtering results", map[string]interface{}{"search_filter": search.SearchFilter})
+		dataArray = applyFilter(filter, dataArray)
+	}
+
+	// Loop through all of the results seeking the matching records
+	var matches []map[string]interface{}
 	for _, item := range dataArray {
 		var hash map[string]interface{}
 
@@ -554,45 +880,61 @@ func (obj *APIObject) FindObject(ctx context.Context, queryString string, search
 		}
 
 		tflog.Debug(ctx, "Examining item in results array", map[string]interface{}{"item": hash})
-		tflog.Debug(ctx, "Comparing search value to item value", map[string]interface{}{"search_value": searchValue, "search_key": searchKey})
 
-		tmp, err := GetStringAtKey(ctx, hash, searchKey)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get the value of '%s' in the results array at '%s': %s", searchKey, resultsKey, err)
-		}
+		if filter == nil {
+			tflog.Debug(ctx, "Comparing search value to item value", map[string]interface{}{"search_value": search.SearchValue, "search_key": search.SearchKey})
 
-		// We found our record
-		if tmp == searchValue {
-			objFound = hash
-			obj.ID, err = GetStringAtKey(ctx, hash, obj.IDAttribute)
+			tmp, err := GetStringAtKey(ctx, hash, search.SearchKey)
 			if err != nil {
-				return nil, fmt.Errorf("failed to find id_attribute '%s' in the record: %s", obj.IDAttribute, err)
+				return nil, fmt.Errorf("failed to get the value of '%s' in the results array at '%s': %s", search.SearchKey, resultsKey, err)
 			}
-
-			tflog.Debug(ctx, "Found ID '%s'", map[string]interface{}{"id": obj.ID})
-
-			// But there is no id attribute???
-			if obj.ID == "" {
-				return nil, fmt.Errorf("the object for '%s'='%s' did not have the id attribute '%s', or the value was empty", searchKey, searchValue, obj.IDAttribute)
+			if tmp != search.SearchValue {
+				continue
 			}
+		}
+
+		matches = append(matches, hash)
+		// Only the unique and last policies need to see the other matches
+		if search.MatchPolicy == "" || search.MatchPolicy == MatchPolicyFirst {
 			break
 		}
 	}
 
+	if len(matches) == 0 {
+		if obj.ID == "" {
+			return nil, fmt.Errorf("%w %s at %s", errObjectNotFound, search.criteria(), searchPath)
+		}
+		return nil, nil
+	}
+
+	// We found our record
+	objFound, err = obj.selectMatch(ctx, search, matches)
+	if err != nil {
+		return nil, err
+	}
+	obj.ID, err = obj.idFromData(ctx, objFound)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find id_attribute '%s' in the record: %s", obj.idAttributeNames(), err)
+	}
+
+	tflog.Debug(ctx, "Found ID '%s'", map[string]interface{}{"id": obj.ID})
+
+	// But there is no id attribute???
 	if obj.ID == "" {
-		return nil, fmt.Errorf("failed to find an object with the '%s' key = '%s' at %s", searchKey, searchValue, searchPath)
+		return nil, fmt.Errorf("the object %s did not have the id attribute '%s', or the value was empty", search.criteria(), obj.idAttributeNames())
 	}
 
 	return objFound, nil
 }
 
-// GetApiData returns a copy of the api_data map from the APIObject
+// GetApiData returns a copy of the api_data map from the APIObject. It is empty when the
+// object is not a JSON object.
 func (obj *APIObject) GetApiData() map[string]string {
 	obj.mux.RLock()
 	defer obj.mux.RUnlock()
 
 	apiData := make(map[string]string)
-	for k, v := range obj.apiData {
+	for k, v := range asObject(obj.apiData) {
 		apiData[k] = fmt.Sprintf("%v", v)
 	}
 	return apiData
@@ -629,3 +971,54 @@ func appendIdToPath(path string) string {
 	}
 	return path + "/{id}"
 }
+
+// idFromData returns the object's id as found in data: the value at IDAttribute, or for a
+// composite id, the values at each of the id attributes joined by the id separator
+func (obj *APIObject) idFromData(ctx context.Context, document interface{}) (string, error) {
+	data, ok := document.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("the document is not a JSON object, so it has no '%s' attribute", obj.idAttributeNames())
+	}
+	if len(obj.idAttributes) == 0 {
+		return GetStringAtKey(ctx, data, obj.IDAttribute)
+	}
+
+	parts := make([]string, 0, len(obj.idAttributes))
+	for _, attr := range obj.idAttributes {
+		part, err := GetStringAtKey(ctx, data, attr)
+		if err != nil {
+			return "", err
+		}
+		// The id could not be split back into its parts
+		if strings.Contains(part, obj.idSeparator) {
+			return "", fmt.Errorf("the value '%s' of id attribute '%s' contains the id separator '%s'", part, attr, obj.idSeparator)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, obj.idSeparator), nil
+}
+
+// idParts splits a composite id into the values of each of the id attributes. It returns
+// nil if the object doesn't have a composite id or the id doesn't have as many parts.
+func (obj *APIObject) idParts() map[string]string {
+	if len(obj.idAttributes) == 0 || obj.ID == "" {
+		return nil
+	}
+	values := strings.Split(obj.ID, obj.idSeparator)
+	if len(values) != len(obj.idAttributes) {
+		return nil
+	}
+	parts := make(map[string]string, len(values))
+	for i, attr := range obj.idAttributes {
+		parts[attr] = values[i]
+	}
+	return parts
+}
+
+// idAttributeNames describes the attributes the id is found at, for messages
+func (obj *APIObject) idAttributeNames() string {
+	if len(obj.idAttributes) == 0 {
+		return obj.IDAttribute
+	}
+	return strings.Join(obj.idAttributes, obj.idSeparator)
+}
@@ -0,0 +1,95 @@
+package apiclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompositeID tests that composite ids are built from several attributes and split back into placeholders
+func TestCompositeID(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.EscapedPath())
+		switch r.URL.Path {
+		case "/records":
+			w.Write([]byte(`[{"zone":"example.com","name":"www","type":"A"},{"zone":"example.com","name":"mail","type":"MX"}]`))
+		default:
+			w.Write([]byte(`{"zone":"example.com","name":"www","type":"A"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewAPIClient(&APIClientOpt{URI: server.URL, Timeout: 2, CreateReturnsObject: true})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("from_data", func(t *testing.T) {
+		obj, err := NewAPIObject(client, &APIObjectOpts{
+			Path:         "/zones/{zone}/records",
+			ReadPath:     "/zones/{zone}/records/{name}",
+			IDAttributes: []string{"zone", "name"},
+			Data:         `{"zone":"example.com","name":"www","type":"A"}`,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "example.com:www", obj.ID)
+	})
+
+	t.Run("from_response", func(t *testing.T) {
+		paths = nil
+		obj, err := NewAPIObject(client, &APIObjectOpts{
+			Path:         "/zones/example.com/records",
+			IDAttributes: []string{"zone", "name"},
+			IDSeparator:  "|",
+			Data:         `{"type":"A"}`,
+		})
+		require.NoError(t, err)
+		require.NoError(t, obj.CreateObject(ctx))
+		assert.Equal(t, "example.com|www", obj.ID)
+		assert.Equal(t, []string{"POST /zones/example.com/records"}, paths)
+	})
+
+	t.Run("split_for_placeholders", func(t *testing.T) {
+		paths = nil
+		// As when importing, only the id is known
+		obj, err := NewAPIObject(client, &APIObjectOpts{
+			Path:         "/records",
+			ReadPath:     "/zones/{zone}/records/{name}",
+			IDAttributes: []string{"zone", "name"},
+			ID:           "example.com:www",
+		})
+		require.NoError(t, err)
+		require.NoError(t, obj.ReadObject(ctx))
+		assert.Equal(t, []string{"GET /zones/example.com/records/www"}, paths)
+	})
+
+	t.Run("search", func(t *testing.T) {
+		obj, err := NewAPIObject(client, &APIObjectOpts{
+			Path:         "/records",
+			IDAttributes: []string{"zone", "name"},
+			Data:         `{"type":"MX"}`,
+		})
+		require.NoError(t, err)
+		_, err = obj.FindObject(ctx, "", "type", "MX", "", "")
+		require.NoError(t, err)
+		assert.Equal(t, "example.com:mail", obj.ID)
+	})
+
+	t.Run("separator_in_value", func(t *testing.T) {
+		_, err := NewAPIObject(client, &APIObjectOpts{
+			Path:         "/records",
+			IDAttributes: []string{"zone", "name"},
+			Data:         `{"zone":"example.com","name":"a:b"}`,
+		})
+		require.NoError(t, err, "The id can still be learned from the API")
+
+		obj, err := NewAPIObject(client, &APIObjectOpts{Path: "/records", IDAttributes: []string{"zone", "name"}})
+		require.NoError(t, err)
+		err = obj.SetDataFromMap(map[string]interface{}{"zone": "example.com", "name": "a:b"})
+		assert.ErrorContains(t, err, "contains the id separator ':'")
+	})
+}
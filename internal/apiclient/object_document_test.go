@@ -0,0 +1,99 @@
+package apiclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNonObjectData tests objects whose data is a JSON array or value rather than an object
+func TestNonObjectData(t *testing.T) {
+	var mux sync.Mutex
+	document := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.Lock()
+		defer mux.Unlock()
+		if r.Method == http.MethodPut {
+			b, _ := io.ReadAll(r.Body)
+			document = string(b)
+		}
+		w.Write([]byte(document))
+	}))
+	defer server.Close()
+
+	client, err := NewAPIClient(&APIClientOpt{URI: server.URL, Timeout: 2, WriteReturnsObject: true})
+	require.NoError(t, err)
+
+	// There is no id_attribute to read the id from
+	_, err = NewAPIObject(client, &APIObjectOpts{Path: "/allowlist", Data: `["10.0.0.0/8"]`})
+	assert.ErrorContains(t, err, "provided data is not a JSON object")
+
+	obj, err := NewAPIObject(client, &APIObjectOpts{
+		Path:         "/allowlist",
+		ID:           "allowlist",
+		CreateMethod: http.MethodPut,
+		ReadPath:     "/allowlist",
+		UpdatePath:   "/allowlist",
+		Data:         `["10.0.0.0/8"]`,
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, obj.CreateObject(ctx))
+	assert.Equal(t, `["10.0.0.0/8"]`, document)
+	assert.Equal(t, "allowlist", obj.ID)
+	assert.JSONEq(t, `["10.0.0.0/8"]`, obj.GetApiResponse())
+	assert.Empty(t, obj.GetApiData())
+
+	require.NoError(t, obj.ReadObject(ctx))
+	assert.Equal(t, "allowlist", obj.ID)
+
+	// Update strategies replace the document as a whole
+	tests := []struct {
+		strategy string
+		expected string
+	}{
+		{UpdateStrategyFull, `["10.0.0.0/8","192.168.0.0/16"]`},
+		{UpdateStrategyMergePatch, `["10.0.0.0/8","192.168.0.0/16"]`},
+		{UpdateStrategyJSONPatch, `[{"op":"replace","path":"","value":["10.0.0.0/8","192.168.0.0/16"]}]`},
+		{UpdateStrategyChangedFields, `["10.0.0.0/8","192.168.0.0/16"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			obj.updateStrategy = tt.strategy
+			require.NoError(t, obj.SetPriorData(`["10.0.0.0/8"]`))
+			obj.data = []interface{}{"10.0.0.0/8", "192.168.0.0/16"}
+			body, _, changed, err := obj.updatePayload()
+			require.NoError(t, err)
+			assert.True(t, changed)
+			assert.JSONEq(t, tt.expected, body)
+
+			// The full data is sent whether it changed or not
+			if tt.strategy == UpdateStrategyFull {
+				return
+			}
+			require.NoError(t, obj.SetPriorData(`["10.0.0.0/8","192.168.0.0/16"]`))
+			_, _, changed, err = obj.updatePayload()
+			require.NoError(t, err)
+			assert.False(t, changed)
+		})
+	}
+
+	obj.updateStrategy = UpdateStrategyFull
+	require.NoError(t, obj.UpdateObject(ctx))
+	assert.Equal(t, `["10.0.0.0/8","192.168.0.0/16"]`, document)
+	assert.JSONEq(t, `["10.0.0.0/8","192.168.0.0/16"]`, obj.GetApiResponse())
+
+	// Values work the same way
+	obj, err = NewAPIObject(client, &APIObjectOpts{Path: "/allowlist", ID: "limit", CreateMethod: http.MethodPut, ReadPath: "/allowlist", Data: `42`})
+	require.NoError(t, err)
+	require.NoError(t, obj.CreateObject(ctx))
+	assert.Equal(t, `42`, document)
+	assert.Equal(t, "42", obj.GetApiResponse())
+}
@@ -412,8 +412,8 @@ func TestReadObject_ReadSearchMultipleResults(t *testing.T) {
 	obj.mux.RLock()
 	defer obj.mux.RUnlock()
 
-	if obj.apiData["name"] != "target-object" {
-		t.Errorf("Expected name to be target-object, got %v", obj.apiData["name"])
+	if asObject(obj.apiData)["name"] != "target-object" {
+		t.Errorf("Expected name to be target-object, got %v", asObject(obj.apiData)["name"])
 	}
 }
 
@@ -192,7 +192,7 @@ func TestAPIObject(t *testing.T) {
 				if testingObjects[testCase].readData == nil {
 					testingObjects[testCase].readData = make(map[string]interface{})
 				}
-				testingObjects[testCase].readData["path"] = "/" + testCase
+				asObject(testingObjects[testCase].readData)["path"] = "/" + testCase
 				err := testingObjects[testCase].ReadObject(ctx)
 				if err != nil {
 					t.Fatalf("api_object_test.go: Failed to read data for test case '%s': %s", testCase, err)
@@ -208,20 +208,20 @@ func TestAPIObject(t *testing.T) {
 		if err != nil {
 			t.Fatalf("api_object_test.go: Failed in copy_keys() test: %s", err)
 		}
-		if testingObjects["normal"].data["Thing"].(string) != "carrot" {
-			t.Fatalf("api_object_test.go: copy_keys for 'normal' object failed. Expected 'Thing' to be 'carrot'', but got '%+v'\n", testingObjects["normal"].data["Thing"])
+		if asObject(testingObjects["normal"].data)["Thing"].(string) != "carrot" {
+			t.Fatalf("api_object_test.go: copy_keys for 'normal' object failed. Expected 'Thing' to be 'carrot'', but got '%+v'\n", asObject(testingObjects["normal"].data)["Thing"])
 		}
 	})
 
 	// Go ahead and update one of our objects
 	t.Run("update_object", func(t *testing.T) {
-		testingObjects["minimal"].data["Thing"] = "spoon"
+		asObject(testingObjects["minimal"].data)["Thing"] = "spoon"
 		err := testingObjects["minimal"].UpdateObject(ctx)
 		if err != nil {
 			t.Fatalf("api_object_test.go: Failed in update_object() test: %s", err)
-		} else if testingObjects["minimal"].apiData["Thing"] != "spoon" {
+		} else if asObject(testingObjects["minimal"].apiData)["Thing"] != "spoon" {
 			t.Fatalf("api_object_test.go: Failed to update 'Thing' field of 'minimal' object. Expected it to be '%s' but it is '%s'\nFull obj: %+v\n",
-				"spoon", testingObjects["minimal"].apiData["Thing"], testingObjects["minimal"])
+				"spoon", asObject(testingObjects["minimal"].apiData)["Thing"], testingObjects["minimal"])
 		}
 	})
 
@@ -230,13 +230,13 @@ func TestAPIObject(t *testing.T) {
 		if testingObjects["minimal"].updateData == nil {
 			testingObjects["minimal"].updateData = make(map[string]interface{})
 		}
-		testingObjects["minimal"].updateData["Thing"] = "knife"
+		asObject(testingObjects["minimal"].updateData)["Thing"] = "knife"
 		err := testingObjects["minimal"].UpdateObject(ctx)
 		if err != nil {
 			t.Fatalf("api_object_test.go: Failed in update_object() test: %s", err)
-		} else if testingObjects["minimal"].apiData["Thing"] != "knife" {
+		} else if asObject(testingObjects["minimal"].apiData)["Thing"] != "knife" {
 			t.Fatalf("api_object_test.go: Failed to update 'Thing' field of 'minimal' object. Expected it to be '%s' but it is '%s'\nFull obj: %+v\n",
-				"knife", testingObjects["minimal"].apiData["Thing"], testingObjects["minimal"])
+				"knife", asObject(testingObjects["minimal"].apiData)["Thing"], testingObjects["minimal"])
 		}
 	})
 
@@ -251,22 +251,22 @@ func TestAPIObject(t *testing.T) {
 
 	// Recreate the one we just got rid of
 	t.Run("create_object", func(t *testing.T) {
-		testingObjects["pet"].data["Thing"] = "dog"
+		asObject(testingObjects["pet"].data)["Thing"] = "dog"
 		err := testingObjects["pet"].CreateObject(ctx)
 		if err != nil {
 			t.Fatalf("api_object_test.go: Failed in create_object() test: %s", err)
-		} else if testingObjects["pet"].apiData["Thing"] != "dog" {
+		} else if asObject(testingObjects["pet"].apiData)["Thing"] != "dog" {
 			t.Fatalf("api_object_test.go: Failed to update 'Thing' field of 'minimal' object. Expected it to be '%s' but it is '%s'\nFull obj: %+v\n",
-				"dog", testingObjects["pet"].apiData["Thing"], testingObjects["pet"])
+				"dog", asObject(testingObjects["pet"].apiData)["Thing"], testingObjects["pet"])
 		}
 
 		// verify it's there
 		err = testingObjects["pet"].ReadObject(ctx)
 		if err != nil {
 			t.Fatalf("api_object_test.go: Failed in read_object() test: %s", err)
-		} else if testingObjects["pet"].apiData["Thing"] != "dog" {
+		} else if asObject(testingObjects["pet"].apiData)["Thing"] != "dog" {
 			t.Fatalf("api_object_test.go: Failed in create_object() test. Object created is xpected it to be '%s' but it is '%s'\nFull obj: %+v\n",
-				"dog", testingObjects["minimal"].apiData["Thing"], testingObjects["minimal"])
+				"dog", asObject(testingObjects["minimal"].apiData)["Thing"], testingObjects["minimal"])
 		}
 	})
 
@@ -304,7 +304,7 @@ func TestAPIObject(t *testing.T) {
 		if testingObjects["pet"].destroyData == nil {
 			testingObjects["pet"].destroyData = make(map[string]interface{})
 		}
-		testingObjects["pet"].destroyData["destroy"] = "true"
+		asObject(testingObjects["pet"].destroyData)["destroy"] = "true"
 		testingObjects["pet"].DeleteObject(ctx)
 		err := testingObjects["pet"].ReadObject(ctx)
 		if err != nil {
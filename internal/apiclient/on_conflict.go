@@ -0,0 +1,51 @@
+package apiclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Conflict modes decide what CreateObject does when the object already exists
+const (
+	OnConflictError          = "error"            // Fail (or create a duplicate if the API allows it)
+	OnConflictAdopt          = "adopt"            // Take over the existing object as it is
+	OnConflictAdoptAndUpdate = "adopt_and_update" // Take over the existing object and update it with the data
+)
+
+// OnConflictModes lists the valid conflict modes
+var OnConflictModes = []string{OnConflictError, OnConflictAdopt, OnConflictAdoptAndUpdate}
+
+// errObjectNotFound is returned (wrapped) by FindObject when no object matches the search
+var errObjectNotFound = errors.New("failed to find an object")
+
+// adoptExisting looks for an object matching read_search and, if there is one, takes over
+// its id and state, updating it with the data for adopt_and_update. It reports whether an
+// object was adopted.
+func (obj *APIObject) adoptExisting(ctx context.Context) (bool, error) {
+	found, err := obj.findByReadSearch(ctx)
+	if errors.Is(err, errObjectNotFound) || (err == nil && found == nil) {
+		tflog.Debug(ctx, "No existing object to adopt", map[string]interface{}{"on_conflict": obj.onConflict})
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to look for an existing object to adopt: %w", err)
+	}
+
+	tflog.Info(ctx, "Adopting existing object", map[string]interface{}{"id": obj.ID, "on_conflict": obj.onConflict})
+	if err := obj.setSearchResult(ctx, found); err != nil {
+		return true, err
+	}
+
+	if obj.onConflict == OnConflictAdoptAndUpdate {
+		// Update strategies other than full send the difference from the object as found,
+		// for the fields in the data only
+		obj.mux.Lock()
+		obj.priorData = RestrictToKeys(obj.apiData, obj.data)
+		obj.mux.Unlock()
+		return true, obj.UpdateObject(ctx)
+	}
+	return true, nil
+}
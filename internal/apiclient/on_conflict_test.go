@@ -0,0 +1,195 @@
+package apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// conflictServer is an API that rejects creating an object with the name of an existing one
+type conflictServer struct {
+	mux      sync.Mutex
+	objects  map[string]map[string]interface{}
+	requests []string
+	bodies   []string
+	// hideFromSearch makes the next search miss the objects, as if they were created after it
+	hideFromSearch bool
+}
+
+func (s *conflictServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+
+	id := strings.TrimPrefix(r.URL.Path, "/api/objects/")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/objects":
+		results := []interface{}{}
+		if !s.hideFromSearch {
+			for _, o := range s.objects {
+				results = append(results, o)
+			}
+		}
+		s.hideFromSearch = false
+		json.NewEncoder(w).Encode(results)
+	case r.Method == http.MethodPost:
+		var o map[string]interface{}
+		b, _ := io.ReadAll(r.Body)
+		json.Unmarshal(b, &o)
+		for _, existing := range s.objects {
+			if existing["name"] == o["name"] {
+				w.WriteHeader(http.StatusConflict)
+				fmt.Fprintf(w, `{"error":"%s already exists"}`, o["name"])
+				return
+			}
+		}
+		o["id"] = fmt.Sprintf("%d", len(s.objects)+1)
+		s.objects[o["id"].(string)] = o
+		json.NewEncoder(w).Encode(o)
+	case r.Method == http.MethodPut:
+		var o map[string]interface{}
+		b, _ := io.ReadAll(r.Body)
+		json.Unmarshal(b, &o)
+		s.objects[id] = o
+		json.NewEncoder(w).Encode(o)
+	case r.Method == http.MethodPatch:
+		b, _ := io.ReadAll(r.Body)
+		s.bodies = append(s.bodies, string(b))
+		existing, _ := json.Marshal(s.objects[id])
+		patched, _ := jsonpatch.MergePatch(existing, b)
+		var o map[string]interface{}
+		json.Unmarshal(patched, &o)
+		s.objects[id] = o
+		json.NewEncoder(w).Encode(o)
+	case s.objects[id] != nil:
+		json.NewEncoder(w).Encode(s.objects[id])
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// TestOnConflict tests that existing objects are adopted instead of created
+func TestOnConflict(t *testing.T) {
+	tests := map[string]struct {
+		onConflict     string
+		updateStrategy string
+		existing       bool
+		hideFromSearch bool
+		wantErr        string
+		wantID         string
+		wantColor      string
+		wantRequests   []string
+		wantBodies     []string
+	}{
+		"error": {
+			onConflict:   OnConflictError,
+			existing:     true,
+			wantErr:      "unexpected response code '409'",
+			wantRequests: []string{"POST /api/objects"},
+		},
+		"adopt": {
+			onConflict:   OnConflictAdopt,
+			existing:     true,
+			wantID:       "1",
+			wantColor:    "red",
+			wantRequests: []string{"GET /api/objects"},
+		},
+		"adopt_and_update": {
+			onConflict:   OnConflictAdoptAndUpdate,
+			existing:     true,
+			wantID:       "1",
+			wantColor:    "blue",
+			wantRequests: []string{"GET /api/objects", "PUT /api/objects/1"},
+		},
+		"adopt_and_update_merge_patch": {
+			onConflict:     OnConflictAdoptAndUpdate,
+			updateStrategy: UpdateStrategyMergePatch,
+			existing:       true,
+			wantID:         "1",
+			wantColor:      "blue",
+			wantRequests:   []string{"GET /api/objects", "PATCH /api/objects/1"},
+			// The id and created fields only the server sets are left alone
+			wantBodies: []string{`{"color":"blue"}`},
+		},
+		"adopt_not_existing": {
+			onConflict:   OnConflictAdopt,
+			wantID:       "1",
+			wantColor:    "blue",
+			wantRequests: []string{"GET /api/objects", "POST /api/objects"},
+		},
+		"adopt_after_conflict": {
+			onConflict:     OnConflictAdopt,
+			existing:       true,
+			hideFromSearch: true,
+			wantID:         "1",
+			wantColor:      "red",
+			wantRequests:   []string{"GET /api/objects", "POST /api/objects", "GET /api/objects"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			api := &conflictServer{objects: map[string]map[string]interface{}{}, hideFromSearch: tc.hideFromSearch}
+			if tc.existing {
+				api.objects["1"] = map[string]interface{}{"id": "1", "name": "foo", "color": "red", "created": "2024-01-01"}
+			}
+			server := httptest.NewServer(api)
+			defer server.Close()
+
+			client, err := NewAPIClient(&APIClientOpt{URI: server.URL, Timeout: 2, WriteReturnsObject: true, IDAttribute: "id"})
+			require.NoError(t, err)
+
+			opts := &APIObjectOpts{
+				Path:           "/api/objects",
+				Data:           `{"name":"foo","color":"blue"}`,
+				OnConflict:     tc.onConflict,
+				ReadSearch:     map[string]string{"search_key": "name", "search_value": "foo"},
+				UpdateStrategy: tc.updateStrategy,
+			}
+			if tc.updateStrategy != "" {
+				opts.UpdateMethod = http.MethodPatch
+			}
+			obj, err := NewAPIObject(client, opts)
+			require.NoError(t, err)
+
+			err = obj.CreateObject(context.Background())
+			assert.Equal(t, tc.wantRequests, api.requests)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantID, obj.ID)
+			assert.Equal(t, tc.wantColor, obj.GetApiData()["color"])
+			assert.Equal(t, tc.wantBodies, api.bodies)
+			if tc.updateStrategy != "" {
+				assert.Equal(t, map[string]interface{}{"id": "1", "name": "foo", "color": "blue", "created": "2024-01-01"}, api.objects["1"])
+			}
+		})
+	}
+}
+
+// TestOnConflictValidation tests that invalid on_conflict settings are rejected
+func TestOnConflictValidation(t *testing.T) {
+	client, err := NewAPIClient(&APIClientOpt{URI: "http://127.0.0.1:8083", Timeout: 2})
+	require.NoError(t, err)
+
+	_, err = NewAPIObject(client, &APIObjectOpts{Path: "/api/objects", ID: "1", OnConflict: "ignore"})
+	assert.ErrorContains(t, err, "on_conflict 'ignore' is not valid")
+
+	_, err = NewAPIObject(client, &APIObjectOpts{Path: "/api/objects", ID: "1", OnConflict: OnConflictAdopt})
+	assert.ErrorContains(t, err, "requires read_search")
+
+	_, err = NewAPIObject(client, &APIObjectOpts{Path: "/api/objects", ID: "1", OnConflict: OnConflictError})
+	assert.NoError(t, err)
+}
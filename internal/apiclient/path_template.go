@@ -0,0 +1,94 @@
+package apiclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// pathPlaceholder matches `{name}` and `{+name}` in a path. Names are keys (or `/` separated
+// paths to nested keys), which keeps JSON in query strings from being mistaken for placeholders.
+var pathPlaceholder = regexp.MustCompile(`\{(\+?)([A-Za-z0-9_.\-/]+)\}`)
+
+// PathPlaceholder is a placeholder found in a path template
+type PathPlaceholder struct {
+	Name string // Key the value is looked up at
+	Raw  bool   // Set for `{+name}`, whose value is inserted without escaping
+}
+
+// PathPlaceholders returns the placeholders in a path template, in the order they appear
+func PathPlaceholders(path string) []PathPlaceholder {
+	var placeholders []PathPlaceholder
+	for _, m := range pathPlaceholder.FindAllStringSubmatch(path, -1) {
+		placeholders = append(placeholders, PathPlaceholder{Name: m[2], Raw: m[1] == "+"})
+	}
+	return placeholders
+}
+
+// PathVariable returns the value of a path_variables entry on the provider
+func (client *APIClient) PathVariable(name string) (string, bool) {
+	v, ok := client.pathVariables[name]
+	return v, ok
+}
+
+// expandPath replaces the placeholders in a path with values from the object. `{id}` is
+// the object's id and the id attributes of a composite id are its parts; any other name is
+// looked up in data, then api_data, then the provider's path_variables. Values are
+// percent-encoded (as a path segment before the `?` and as a query value after it) unless
+// the placeholder is written `{+name}`.
+func (obj *APIObject) expandPath(ctx context.Context, path string) (string, error) {
+	if !strings.Contains(path, "{") {
+		return path, nil
+	}
+
+	obj.mux.RLock()
+	defer obj.mux.RUnlock()
+
+	var errs []string
+	expand := func(s string, escape func(string) string) string {
+		return pathPlaceholder.ReplaceAllStringFunc(s, func(m string) string {
+			sub := pathPlaceholder.FindStringSubmatch(m)
+			v, ok := obj.pathValue(ctx, sub[2])
+			if !ok {
+				errs = append(errs, m)
+				return m
+			}
+			if sub[1] == "+" {
+				return v
+			}
+			return escape(v)
+		})
+	}
+
+	var expanded string
+	if before, after, found := strings.Cut(path, "?"); found {
+		expanded = expand(before, url.PathEscape) + "?" + expand(after, url.QueryEscape)
+	} else {
+		expanded = expand(path, url.PathEscape)
+	}
+
+	if len(errs) > 0 {
+		return "", fmt.Errorf("path '%s' has placeholders that do not resolve to a string, number or boolean in data, api_data or the provider's path_variables: %s", path, strings.Join(errs, ", "))
+	}
+	return expanded, nil
+}
+
+// pathValue looks up the value of a path placeholder
+func (obj *APIObject) pathValue(ctx context.Context, name string) (string, bool) {
+	if name == "id" {
+		return obj.ID, true
+	}
+	// The parts of a composite id are known even before the object is read, as when importing
+	if v, ok := obj.idParts()[name]; ok {
+		return v, true
+	}
+	if v, err := GetStringAtKey(ctx, asObject(obj.data), name); err == nil {
+		return v, true
+	}
+	if v, err := GetStringAtKey(ctx, asObject(obj.apiData), name); err == nil {
+		return v, true
+	}
+	return obj.apiClient.PathVariable(name)
+}
@@ -0,0 +1,104 @@
+package apiclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExpandPath tests that path placeholders are resolved and escaped
+func TestExpandPath(t *testing.T) {
+	client, err := NewAPIClient(&APIClientOpt{
+		URI:           "http://127.0.0.1:8083",
+		Timeout:       2,
+		PathVariables: map[string]string{"tenant": "acme corp", "org_id": "from-provider"},
+	})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		id       string
+		path     string
+		want     string
+		wantErr  string
+		response string
+	}{
+		"id":                 {id: "1", path: "/api/objects/{id}", want: "/api/objects/1"},
+		"id_escaped":         {id: "a/b c#d", path: "/api/objects/{id}", want: "/api/objects/a%2Fb%20c%23d"},
+		"id_raw":             {id: "folder/file", path: "/files/{+id}", want: "/files/folder/file"},
+		"data_field":         {id: "1", path: "/orgs/{org_id}/teams/{id}", want: "/orgs/org%201/teams/1"},
+		"nested_data_field":  {id: "1", path: "/owners/{owner/name}/objects/{id}", want: "/owners/bob/objects/1"},
+		"number_field":       {id: "1", path: "/sizes/{size}", want: "/sizes/10"},
+		"api_data_field":     {id: "1", path: "/revisions/{revision}", want: "/revisions/7", response: `{"id":"1","revision":7}`},
+		"provider_variable":  {id: "1", path: "/tenants/{tenant}/objects/{id}", want: "/tenants/acme%20corp/objects/1"},
+		"query_string":       {id: "a&b", path: "/objects/{org_id}?id={id}&tenant={tenant}", want: "/objects/org%201?id=a%26b&tenant=acme+corp"},
+		"json_query_string":  {id: "1", path: `/objects?filter={"id":1}`, want: `/objects?filter={"id":1}`},
+		"unresolved":         {id: "1", path: "/orgs/{parent_id}/teams/{missing}", wantErr: "{parent_id}, {missing}"},
+		"not_a_scalar_value": {id: "1", path: "/owners/{owner}", wantErr: "{owner}"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			obj, err := NewAPIObject(client, &APIObjectOpts{
+				Path:        "/api/objects",
+				ID:          tc.id,
+				Data:        `{"org_id":"org 1","owner":{"name":"bob"},"size":10}`,
+				APIResponse: tc.response,
+			})
+			require.NoError(t, err)
+
+			got, err := obj.expandPath(context.Background(), tc.path)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+// TestPathPlaceholders tests that placeholders are found in path templates
+func TestPathPlaceholders(t *testing.T) {
+	assert.Nil(t, PathPlaceholders("/api/objects"))
+	assert.Equal(t, []PathPlaceholder{
+		{Name: "org_id"},
+		{Name: "id", Raw: true},
+		{Name: "owner/name"},
+	}, PathPlaceholders(`/orgs/{org_id}/teams/{+id}?owner={owner/name}&filter={"a":1}`))
+}
+
+// TestPathTemplateRequests tests that objects are read, updated and deleted at their expanded paths
+func TestPathTemplateRequests(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.EscapedPath())
+		w.Write([]byte(`{"id":"team/1","org_id":"org-1","name":"Foo"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewAPIClient(&APIClientOpt{URI: server.URL, Timeout: 2, WriteReturnsObject: true})
+	require.NoError(t, err)
+
+	obj, err := NewAPIObject(client, &APIObjectOpts{
+		Path: "/orgs/{org_id}/teams",
+		Data: `{"id":"team/1","org_id":"org-1","name":"Foo"}`,
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, obj.CreateObject(ctx))
+	require.NoError(t, obj.ReadObject(ctx))
+	require.NoError(t, obj.UpdateObject(ctx))
+	require.NoError(t, obj.DeleteObject(ctx))
+
+	assert.Equal(t, []string{
+		"POST /orgs/org-1/teams",
+		"GET /orgs/org-1/teams/team%2F1",
+		"PUT /orgs/org-1/teams/team%2F1",
+		"DELETE /orgs/org-1/teams/team%2F1",
+	}, paths)
+}
@@ -0,0 +1,73 @@
+package apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// compilePatch decodes the RFC 6902 JSON Patch set as the named option, if any
+func compilePatch(ctx context.Context, name string, patch string) (jsonpatch.Patch, error) {
+	if patch == "" {
+		return nil, nil
+	}
+	tflog.Debug(ctx, "Compiling "+name, map[string]interface{}{name: patch})
+	compiled, err := jsonpatch.DecodePatch([]byte(patch))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile %s: %w", name, err)
+	}
+	return compiled, nil
+}
+
+// applyPatch applies a patch to a JSON document. Removing a field the document doesn't
+// have is not an error, as APIs often leave out empty fields.
+func applyPatch(patch jsonpatch.Patch, doc []byte) ([]byte, error) {
+	options := jsonpatch.NewApplyOptions()
+	options.AllowMissingPathOnRemove = true
+	return patch.ApplyWithOptions(doc, options)
+}
+
+// patchResponse applies read_patch to an object returned by the API, so that it has
+// the same shape as the data
+func (obj *APIObject) patchResponse(response string) (string, error) {
+	if obj.readPatch == nil {
+		return response, nil
+	}
+	patched, err := applyPatch(obj.readPatch, []byte(response))
+	if err != nil {
+		return "", fmt.Errorf("failed to apply read_patch: %w", err)
+	}
+	return string(patched), nil
+}
+
+// writeData returns the data as it is sent to the API, with write_patch applied
+func (obj *APIObject) writeData(data interface{}) (interface{}, error) {
+	if obj.writePatch == nil || data == nil {
+		return data, nil
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	if b, err = applyPatch(obj.writePatch, b); err != nil {
+		return nil, fmt.Errorf("failed to apply write_patch: %w", err)
+	}
+	var patched interface{}
+	if err := DecodeJSON(b, &patched); err != nil {
+		return nil, fmt.Errorf("failed to parse the data patched by write_patch: %w", err)
+	}
+	return patched, nil
+}
+
+// writePayload marshals the data as it is sent to the API, with write_patch applied and
+// in the request envelope
+func (obj *APIObject) writePayload(data interface{}) ([]byte, error) {
+	data, err := obj.writeData(data)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(obj.wrapRequest(data))
+}
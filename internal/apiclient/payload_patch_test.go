@@ -0,0 +1,86 @@
+package apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wrappingServer is an API that keeps an object's settings under "spec" and adds a
+// server-only "status" field to it
+type wrappingServer struct {
+	mux    sync.Mutex
+	object map[string]interface{}
+	bodies []string
+}
+
+func (s *wrappingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if r.Method == http.MethodPost || r.Method == http.MethodPut || r.Method == http.MethodPatch {
+		b, _ := io.ReadAll(r.Body)
+		s.bodies = append(s.bodies, string(b))
+		var o map[string]interface{}
+		json.Unmarshal(b, &o)
+		s.object = map[string]interface{}{"id": "1", "spec": o["spec"], "status": "ready"}
+	}
+	if s.object == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(s.object)
+}
+
+// TestReadWritePatch tests that read_patch and write_patch reshape objects read from
+// and sent to the API
+func TestReadWritePatch(t *testing.T) {
+	for _, writeReturnsObject := range []bool{true, false} {
+		api := &wrappingServer{}
+		server := httptest.NewServer(api)
+		defer server.Close()
+
+		client, err := NewAPIClient(&APIClientOpt{URI: server.URL, Timeout: 2, WriteReturnsObject: writeReturnsObject, IDAttribute: "id"})
+		require.NoError(t, err)
+
+		obj, err := NewAPIObject(client, &APIObjectOpts{
+			Path:           "/api/objects",
+			ID:             "1",
+			Data:           `{"name":"foo","size":1}`,
+			UpdateStrategy: UpdateStrategyJSONPatch,
+			ReadPatch:      `[{"op":"move","from":"/spec/name","path":"/name"},{"op":"move","from":"/spec/size","path":"/size"},{"op":"remove","path":"/spec"},{"op":"remove","path":"/status"}]`,
+			WritePatch:     `[{"op":"add","path":"/spec","value":{}},{"op":"move","from":"/name","path":"/spec/name"},{"op":"move","from":"/size","path":"/spec/size"}]`,
+		})
+		require.NoError(t, err)
+
+		ctx := context.Background()
+		require.NoError(t, obj.CreateObject(ctx))
+		assert.JSONEq(t, `{"spec":{"name":"foo","size":1}}`, api.bodies[0])
+		assert.JSONEq(t, `{"id":"1","name":"foo","size":1}`, obj.GetApiResponse())
+
+		// The update is worked out between the data as it is sent to the API
+		require.NoError(t, obj.SetPriorData(`{"name":"foo","size":1}`))
+		obj.data = map[string]interface{}{"name": "foo", "size": 2}
+		body, _, changed, err := obj.updatePayload()
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.JSONEq(t, `[{"op":"replace","path":"/spec/size","value":2}]`, body)
+
+		require.NoError(t, obj.ReadObject(ctx))
+		assert.Equal(t, map[string]string{"id": "1", "name": "foo", "size": "1"}, obj.GetApiData())
+	}
+
+	client, err := NewAPIClient(&APIClientOpt{URI: "http://127.0.0.1:8083", Timeout: 2})
+	require.NoError(t, err)
+	_, err = NewAPIObject(client, &APIObjectOpts{Path: "/api/objects", ID: "1", ReadPatch: `{"op":"remove"}`})
+	assert.ErrorContains(t, err, "failed to compile read_patch")
+	_, err = NewAPIObject(client, &APIObjectOpts{Path: "/api/objects", ID: "1", WritePatch: `[{"op":"bad","path":"/x"}]`})
+	assert.ErrorContains(t, err, "failed to compile write_patch")
+}
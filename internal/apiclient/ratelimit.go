@@ -0,0 +1,251 @@
+package apiclient
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/time/rate"
+)
+
+// RateLimitBudget is a separate request budget for requests matching Method and/or PathPrefix.
+// Requests that do not match any budget use the provider-wide rate_limit.
+type RateLimitBudget struct {
+	Method     string
+	PathPrefix string
+	RateLimit  float64 // RateLimit in requests per second
+}
+
+// rateBudget is a single token bucket along with the adaptive state learned from the server
+type rateBudget struct {
+	method      string
+	pathPrefix  string
+	baseLimit   rate.Limit
+	limiter     *rate.Limiter
+	pausedUntil time.Time // Set when the server reports the quota is exhausted
+}
+
+// rateLimiter throttles outbound requests. When adaptive is set, the limits are adjusted
+// according to the X-RateLimit-* and RateLimit headers the server returns.
+type rateLimiter struct {
+	mux      sync.Mutex
+	budgets  []*rateBudget
+	fallback *rateBudget
+	adaptive bool
+}
+
+// rateLimitStatus is the quota information advertised by the server in a response
+type rateLimitStatus struct {
+	limit     int64 // -1 if not advertised
+	remaining int64 // -1 if not advertised
+	reset     time.Duration
+	hasReset  bool
+}
+
+// newRateLimiter builds the rate limiter for a client. Returns nil if no limiting is configured.
+func newRateLimiter(ctx context.Context, opt *APIClientOpt) *rateLimiter {
+	if !isLimited(opt.RateLimit) && len(opt.RateLimitBudgets) == 0 && !opt.AdaptiveRateLimit {
+		tflog.Info(ctx, "rate limiting disabled", nil)
+		return nil
+	}
+
+	limiter := &rateLimiter{
+		fallback: newRateBudget("", "", opt.RateLimit),
+		adaptive: opt.AdaptiveRateLimit,
+	}
+	for _, b := range opt.RateLimitBudgets {
+		limiter.budgets = append(limiter.budgets, newRateBudget(b.Method, b.PathPrefix, b.RateLimit))
+	}
+
+	tflog.Info(ctx, "rate limit configured", map[string]interface{}{
+		"rateLimit": opt.RateLimit,
+		"budgets":   len(opt.RateLimitBudgets),
+		"adaptive":  opt.AdaptiveRateLimit,
+	})
+	return limiter
+}
+
+// isLimited reports whether a configured rate actually restricts anything.
+// The provider passes math.MaxFloat64 when rate_limit is not set.
+func isLimited(limit float64) bool {
+	return limit > 0 && limit < math.MaxFloat64 && !math.IsInf(limit, 1)
+}
+
+func newRateBudget(method string, pathPrefix string, limit float64) *rateBudget {
+	baseLimit := rate.Inf
+	// Bucket size determines burst capacity - at minimum 1 request, otherwise rounded rate
+	bucketSize := 1
+	if isLimited(limit) {
+		baseLimit = rate.Limit(limit)
+		bucketSize = int(math.Max(math.Round(limit), 1))
+	}
+
+	return &rateBudget{
+		method:     strings.ToUpper(method),
+		pathPrefix: pathPrefix,
+		baseLimit:  baseLimit,
+		limiter:    rate.NewLimiter(baseLimit, bucketSize),
+	}
+}
+
+// budgetFor returns the first budget matching the request, or the provider-wide budget
+func (l *rateLimiter) budgetFor(method string, path string) *rateBudget {
+	for _, b := range l.budgets {
+		if b.method != "" && b.method != strings.ToUpper(method) {
+			continue
+		}
+		if !strings.HasPrefix(path, b.pathPrefix) {
+			continue
+		}
+		return b
+	}
+	return l.fallback
+}
+
+// Wait blocks until the request is allowed to proceed or the context is done
+func (l *rateLimiter) Wait(ctx context.Context, method string, path string) error {
+	b := l.budgetFor(method, path)
+
+	l.mux.Lock()
+	pause := time.Until(b.pausedUntil)
+	l.mux.Unlock()
+
+	if pause > 0 {
+		tflog.Info(ctx, "Rate limit quota exhausted, pausing until reset", map[string]interface{}{"method": method, "path": path, "wait": pause.String()})
+		timer := time.NewTimer(pause)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if err := b.limiter.Wait(ctx); err != nil {
+		// The limiter refuses to wait past a deadline without waiting for it - report it as the context error
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, ok := ctx.Deadline(); ok {
+			return fmt.Errorf("%w: %v", context.DeadlineExceeded, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// Observe adjusts the budget used by the request according to the quota headers in the response
+func (l *rateLimiter) Observe(ctx context.Context, method string, path string, header http.Header) {
+	if !l.adaptive {
+		return
+	}
+
+	status, ok := parseRateLimitHeaders(header, time.Now())
+	if !ok {
+		return
+	}
+
+	b := l.budgetFor(method, path)
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	switch {
+	case status.remaining == 0 && status.hasReset:
+		b.pausedUntil = time.Now().Add(status.reset)
+		tflog.Info(ctx, "Server reports rate limit quota exhausted", map[string]interface{}{"reset": status.reset.String()})
+	case status.remaining > 0 && status.hasReset && status.reset > 0:
+		// Spread the remaining quota evenly over the time left in the window
+		target := rate.Limit(float64(status.remaining) / status.reset.Seconds())
+		b.setLimit(ctx, target)
+	case status.remaining > 0 && status.limit > 0 && b.baseLimit != rate.Inf:
+		// No reset window advertised; scale our own rate by the fraction of quota left
+		target := b.baseLimit * rate.Limit(float64(status.remaining)/float64(status.limit))
+		b.setLimit(ctx, target)
+	}
+}
+
+// setLimit applies an adaptive limit without ever exceeding the configured base rate
+func (b *rateBudget) setLimit(ctx context.Context, target rate.Limit) {
+	if target > b.baseLimit {
+		target = b.baseLimit
+	}
+	if target != b.limiter.Limit() {
+		tflog.Debug(ctx, "Adjusting rate limit from server headers", map[string]interface{}{"from": float64(b.limiter.Limit()), "to": float64(target)})
+		b.limiter.SetLimit(target)
+	}
+}
+
+// parseRateLimitHeaders reads the quota advertised by the server. Supported are the
+// X-RateLimit-{Limit,Remaining,Reset} headers, the RateLimit-{Limit,Remaining,Reset}
+// headers and the combined RateLimit header (`limit=100, remaining=50, reset=30` as
+// well as the structured `"policy";r=50;t=30` form, whose quota `q` is advertised in the
+// RateLimit-Policy header). Returns false if the response carries no rate limit information.
+func parseRateLimitHeaders(header http.Header, now time.Time) (rateLimitStatus, bool) {
+	status := rateLimitStatus{limit: -1, remaining: -1}
+	found := false
+
+	setValue := func(key string, value string) {
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil || n < 0 {
+			return
+		}
+		switch key {
+		case "limit":
+			status.limit = int64(n)
+		case "remaining", "r":
+			status.remaining = int64(n)
+		case "reset", "t":
+			status.reset = resetDuration(n, now)
+			status.hasReset = true
+		default:
+			return
+		}
+		found = true
+	}
+
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		for _, key := range []string{"limit", "remaining", "reset"} {
+			if v := header.Get(prefix + key); v != "" {
+				setValue(key, v)
+			}
+		}
+	}
+
+	if v := header.Get("RateLimit"); v != "" {
+		for _, item := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ';' }) {
+			if k, val, ok := strings.Cut(item, "="); ok {
+				setValue(strings.ToLower(strings.TrimSpace(k)), val)
+			}
+		}
+	}
+
+	if v := header.Get("RateLimit-Policy"); v != "" {
+		for _, item := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ';' }) {
+			if k, val, ok := strings.Cut(item, "="); ok && strings.ToLower(strings.TrimSpace(k)) == "q" {
+				setValue("limit", val)
+			}
+		}
+	}
+
+	return status, found
+}
+
+// resetDuration interprets a reset value as either seconds until reset or,
+// for values that can only be a timestamp, as seconds since the epoch
+func resetDuration(value float64, now time.Time) time.Duration {
+	if value > 1e9 {
+		d := time.Unix(int64(value), 0).Sub(now)
+		if d < 0 {
+			return 0
+		}
+		return d
+	}
+	return time.Duration(value * float64(time.Second))
+}
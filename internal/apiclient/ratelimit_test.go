@@ -0,0 +1,246 @@
+package apiclient
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestParseRateLimitHeaders(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name      string
+		headers   map[string]string
+		found     bool
+		limit     int64
+		remaining int64
+		reset     time.Duration
+	}{
+		{
+			name:    "no_headers",
+			headers: map[string]string{},
+			found:   false,
+		},
+		{
+			name: "x_ratelimit_relative_reset",
+			headers: map[string]string{
+				"X-RateLimit-Limit":     "100",
+				"X-RateLimit-Remaining": "25",
+				"X-RateLimit-Reset":     "30",
+			},
+			found:     true,
+			limit:     100,
+			remaining: 25,
+			reset:     30 * time.Second,
+		},
+		{
+			name: "x_ratelimit_epoch_reset",
+			headers: map[string]string{
+				"X-RateLimit-Remaining": "0",
+				"X-RateLimit-Reset":     "1700000045",
+			},
+			found:     true,
+			limit:     -1,
+			remaining: 0,
+			reset:     45 * time.Second,
+		},
+		{
+			name: "ratelimit_separate_fields",
+			headers: map[string]string{
+				"RateLimit-Limit":     "10",
+				"RateLimit-Remaining": "5",
+				"RateLimit-Reset":     "2",
+			},
+			found:     true,
+			limit:     10,
+			remaining: 5,
+			reset:     2 * time.Second,
+		},
+		{
+			name: "ratelimit_combined",
+			headers: map[string]string{
+				"RateLimit": "limit=100, remaining=50, reset=60",
+			},
+			found:     true,
+			limit:     100,
+			remaining: 50,
+			reset:     60 * time.Second,
+		},
+		{
+			name: "ratelimit_structured",
+			headers: map[string]string{
+				"RateLimit": `"default";r=7;t=3`,
+			},
+			found:     true,
+			limit:     -1,
+			remaining: 7,
+			reset:     3 * time.Second,
+		},
+		{
+			name: "ratelimit_structured_with_policy",
+			headers: map[string]string{
+				"RateLimit":        `"default";r=50;t=30`,
+				"RateLimit-Policy": `"default";q=100;w=60`,
+			},
+			found:     true,
+			limit:     100,
+			remaining: 50,
+			reset:     30 * time.Second,
+		},
+		{
+			name: "garbage_is_ignored",
+			headers: map[string]string{
+				"X-RateLimit-Remaining": "lots",
+			},
+			found: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for k, v := range tt.headers {
+				header.Set(k, v)
+			}
+
+			status, found := parseRateLimitHeaders(header, now)
+			assert.Equal(t, tt.found, found)
+			if !tt.found {
+				return
+			}
+			assert.Equal(t, tt.limit, status.limit, "limit")
+			assert.Equal(t, tt.remaining, status.remaining, "remaining")
+			assert.Equal(t, tt.reset, status.reset, "reset")
+		})
+	}
+}
+
+func TestNewRateLimiter(t *testing.T) {
+	ctx := context.Background()
+
+	assert.Nil(t, newRateLimiter(ctx, &APIClientOpt{}), "No limiter when nothing is configured")
+	assert.Nil(t, newRateLimiter(ctx, &APIClientOpt{RateLimit: math.MaxFloat64}), "No limiter for the provider's unset default")
+
+	limiter := newRateLimiter(ctx, &APIClientOpt{RateLimit: math.MaxFloat64, AdaptiveRateLimit: true})
+	require.NotNil(t, limiter, "Adaptive limiting needs a limiter even without a base rate")
+	assert.Equal(t, rate.Inf, limiter.fallback.baseLimit)
+
+	// Waiting on an unlimited budget must not fail (previously an error here was silently discarded)
+	assert.NoError(t, limiter.Wait(ctx, "GET", "/api/objects"))
+}
+
+func TestRateLimiterBudgets(t *testing.T) {
+	limiter := newRateLimiter(context.Background(), &APIClientOpt{
+		RateLimit: 10,
+		RateLimitBudgets: []RateLimitBudget{
+			{Method: "post", PathPrefix: "/api/objects", RateLimit: 1},
+			{PathPrefix: "/api/search", RateLimit: 2},
+		},
+	})
+	require.NotNil(t, limiter)
+
+	assert.Equal(t, rate.Limit(1), limiter.budgetFor("POST", "/api/objects/1").baseLimit)
+	assert.Equal(t, rate.Limit(10), limiter.budgetFor("GET", "/api/objects/1").baseLimit)
+	assert.Equal(t, rate.Limit(2), limiter.budgetFor("GET", "/api/search?q=foo").baseLimit)
+	assert.Equal(t, rate.Limit(10), limiter.budgetFor("DELETE", "/other").baseLimit)
+}
+
+func TestRateLimiterAdaptive(t *testing.T) {
+	ctx := context.Background()
+	limiter := newRateLimiter(ctx, &APIClientOpt{RateLimit: 100, AdaptiveRateLimit: true})
+	require.NotNil(t, limiter)
+
+	header := http.Header{}
+	header.Set("X-RateLimit-Remaining", "10")
+	header.Set("X-RateLimit-Reset", "5")
+	limiter.Observe(ctx, "GET", "/api/objects", header)
+	assert.Equal(t, rate.Limit(2), limiter.fallback.limiter.Limit(), "Remaining quota should be spread over the reset window")
+
+	// More quota than the configured limit never raises the rate above it
+	header.Set("X-RateLimit-Remaining", "1000")
+	header.Set("X-RateLimit-Reset", "1")
+	limiter.Observe(ctx, "GET", "/api/objects", header)
+	assert.Equal(t, rate.Limit(100), limiter.fallback.limiter.Limit())
+
+	// Exhausted quota pauses requests until reset; a cancelled context aborts the wait
+	header.Set("X-RateLimit-Remaining", "0")
+	header.Set("X-RateLimit-Reset", "60")
+	limiter.Observe(ctx, "GET", "/api/objects", header)
+
+	cancelCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := limiter.Wait(cancelCtx, "GET", "/api/objects")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second, "Wait should be aborted by the context")
+}
+
+func TestRateLimiterIgnoresHeadersWhenNotAdaptive(t *testing.T) {
+	ctx := context.Background()
+	limiter := newRateLimiter(ctx, &APIClientOpt{RateLimit: 100})
+	require.NotNil(t, limiter)
+
+	header := http.Header{}
+	header.Set("X-RateLimit-Remaining", "0")
+	header.Set("X-RateLimit-Reset", "60")
+	limiter.Observe(ctx, "GET", "/api/objects", header)
+
+	assert.True(t, limiter.fallback.pausedUntil.IsZero())
+	assert.Equal(t, rate.Limit(100), limiter.fallback.limiter.Limit())
+}
+
+func TestSendRequestAdaptiveRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("RateLimit", "limit=10, remaining=0, reset=30")
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewAPIClient(&APIClientOpt{
+		URI:               server.URL,
+		Timeout:           2,
+		AdaptiveRateLimit: true,
+	})
+	require.NoError(t, err)
+
+	_, _, err = client.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+	require.NoError(t, err)
+
+	// The server reported the quota is exhausted, so the next request must wait for the reset
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, _, err = client.SendRequest(ctx, "GET", "/api/objects/1", "", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "aborted while waiting for rate limit")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestSendRequestAdaptiveRateLimitRetriesExhausted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client, err := NewAPIClient(&APIClientOpt{
+		URI:               server.URL,
+		Timeout:           2,
+		AdaptiveRateLimit: true,
+	})
+	require.NoError(t, err)
+
+	_, _, err = client.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+	require.Error(t, err)
+
+	// The 429 the retries gave up on still tells the limiter to wait for the reset
+	assert.False(t, client.rateLimiter.fallback.pausedUntil.IsZero())
+}
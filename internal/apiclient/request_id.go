@@ -0,0 +1,57 @@
+package apiclient
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DefaultRequestIDHeader is the header used to send the per-request correlation ID when none is configured
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// RequestError is returned by SendRequest when a request fails, either because the API returned
+// a non-2xx response or because no response was received. It carries the correlation ID sent
+// with the request and, if the API returned one, the API's own request ID, so failures can be
+// matched up with the API's logs.
+type RequestError struct {
+	Method          string
+	Path            string
+	StatusCode      int    // Status code of the response, 0 if none was received
+	Body            string // Body of the response
+	RequestID       string // Correlation ID sent in the request ID header
+	ServerRequestID string // Request ID header returned by the API, if any
+	Err             error  // Underlying error if the request did not complete
+
+	csrfRejected bool        // Whether the API rejected the CSRF token of the request
+	header       http.Header // Headers of the response the retries gave up on, if any
+}
+
+func (e *RequestError) Error() string {
+	var msg string
+	if e.Err != nil {
+		msg = e.Err.Error()
+	} else {
+		msg = fmt.Sprintf("unexpected response code '%d': %s", e.StatusCode, e.Body)
+	}
+
+	ids := []string{"request ID: " + e.RequestID}
+	if e.ServerRequestID != "" && e.ServerRequestID != e.RequestID {
+		ids = append(ids, "server request ID: "+e.ServerRequestID)
+	}
+	return fmt.Sprintf("%s (%s)", msg, strings.Join(ids, ", "))
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// newRequestID returns a random (version 4) UUID
+func newRequestID() string {
+	var b [16]byte
+	// crypto/rand.Read never returns an error
+	rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40 // Version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // Variant 10
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,95 @@
+package apiclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRequestID(t *testing.T) {
+	uuidV4 := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	seen := map[string]bool{}
+	for range 100 {
+		id := newRequestID()
+		assert.Regexp(t, uuidV4, id)
+		assert.False(t, seen[id], "Request IDs should be unique")
+		seen[id] = true
+	}
+}
+
+// TestSendRequestCorrelationID tests that the request ID and User-Agent are sent and failures report the IDs
+func TestSendRequestCorrelationID(t *testing.T) {
+	var received http.Header
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.Header().Set("X-Correlation-ID", "server-abc")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error":"boom"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewAPIClient(&APIClientOpt{
+		URI:             server.URL,
+		Timeout:         2,
+		RequestIDHeader: "X-Correlation-ID",
+		UserAgent:       "Terraform/1.9.0 terraform-provider-restapi/1.2.3",
+	})
+	require.NoError(t, err)
+
+	_, _, err = client.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+	require.Error(t, err)
+
+	requestID := received.Get("X-Correlation-ID")
+	require.NotEmpty(t, requestID)
+	assert.Equal(t, "Terraform/1.9.0 terraform-provider-restapi/1.2.3", received.Get("User-Agent"))
+
+	var requestErr *RequestError
+	require.True(t, errors.As(err, &requestErr))
+	assert.Equal(t, requestID, requestErr.RequestID)
+	assert.Equal(t, "server-abc", requestErr.ServerRequestID)
+	assert.Equal(t, "/api/objects/1", requestErr.Path)
+	assert.Contains(t, err.Error(), "giving up after 1 attempt(s)")
+	assert.Contains(t, err.Error(), "request ID: "+requestID)
+	assert.Contains(t, err.Error(), "server request ID: server-abc")
+
+	// Non-retryable failures include the response
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Correlation-ID", "server-def")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"bad"}`))
+	})
+	_, status, err := client.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+	require.True(t, errors.As(err, &requestErr))
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, http.StatusBadRequest, requestErr.StatusCode)
+	assert.Equal(t, `{"error":"bad"}`, requestErr.Body)
+	assert.Equal(t, "server-def", requestErr.ServerRequestID)
+	assert.Contains(t, err.Error(), "unexpected response code '400'")
+}
+
+// TestSendRequestCorrelationIDConnectionError tests that failures without a response also carry the request ID
+func TestSendRequestCorrelationIDConnectionError(t *testing.T) {
+	client, err := NewAPIClient(&APIClientOpt{
+		URI:     "http://127.0.0.1:9999",
+		Timeout: 2,
+	})
+	require.NoError(t, err)
+
+	_, _, err = client.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+	require.Error(t, err)
+
+	var requestErr *RequestError
+	require.True(t, errors.As(err, &requestErr))
+	assert.NotEmpty(t, requestErr.RequestID)
+	assert.Zero(t, requestErr.StatusCode)
+	assert.Contains(t, err.Error(), "connection refused")
+	assert.Contains(t, err.Error(), "request ID: "+requestErr.RequestID)
+}
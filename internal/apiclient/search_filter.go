@@ -0,0 +1,131 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ohler55/ojg/jp"
+)
+
+// SearchOpts are the criteria FindObjectWithOpts looks for an object with. Either
+// SearchFilter or both SearchKey and SearchValue must be set.
+type SearchOpts struct {
+	QueryString  string // Query string to send with the search request
+	SearchKey    string // Key of the field compared with SearchValue
+	SearchValue  string
+	SearchFilter string // JSONPath filter expression the object must match; see compileSearchFilter
+	ResultsKey   string // Where the results array is in the response
+	SearchData   string // Body to send with the search request
+	MatchPolicy  string // Which object to return when several match; see MatchPolicies (defaults to first)
+}
+
+// compile validates the search options and compiles the filter, if any
+func (s *SearchOpts) compile() (jp.Expr, error) {
+	if s.MatchPolicy != "" && !slices.Contains(MatchPolicies, s.MatchPolicy) {
+		return nil, fmt.Errorf("match_policy '%s' is not valid, must be one of %s", s.MatchPolicy, strings.Join(MatchPolicies, ", "))
+	}
+	if s.SearchFilter == "" {
+		if s.SearchKey == "" || s.SearchValue == "" {
+			return nil, fmt.Errorf("either search_filter or both search_key and search_value must be set to search for an object")
+		}
+		return nil, nil
+	}
+	if s.SearchKey != "" || s.SearchValue != "" {
+		return nil, fmt.Errorf("search_filter cannot be combined with search_key and search_value")
+	}
+	return compileSearchFilter(s.SearchFilter)
+}
+
+// criteria describes the search criteria for messages
+func (s *SearchOpts) criteria() string {
+	if s.SearchFilter != "" {
+		return fmt.Sprintf("matching search_filter '%s'", s.SearchFilter)
+	}
+	return fmt.Sprintf("with the '%s' key = '%s'", s.SearchKey, s.SearchValue)
+}
+
+// compileSearchFilter compiles a JSONPath filter expression such as
+// `@.name == 'foo' && @.env =~ /(?i)^prod/` into a JSONPath selecting the matching
+// elements of the results array. A full JSONPath starting with `$` is used as is.
+func compileSearchFilter(filter string) (jp.Expr, error) {
+	path := strings.TrimSpace(filter)
+	if !strings.HasPrefix(path, "$") {
+		path = fmt.Sprintf("$[?(%s)]", path)
+	}
+	expr, err := jp.ParseString(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid search_filter '%s': %w", filter, err)
+	}
+	return expr, nil
+}
+
+// filterStringEscaper escapes the characters that would end a quoted string in a filter
+var filterStringEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, `"`, `\"`)
+
+// escapeFilterString escapes a value substituted into a quoted string of a filter expression,
+// such as the {id} of `@.id == '{id}'`, so that it can't end the string and change the filter
+func escapeFilterString(value string) string {
+	return filterStringEscaper.Replace(value)
+}
+
+// applyFilter returns the results the filter selects. The filter is evaluated on a copy
+// of the results with numbers it can compare, and what it locates there is taken from the
+// results themselves, so that numbers keep their precision.
+func applyFilter(filter jp.Expr, results []interface{}) []interface{} {
+	locations := filter.Locate(filterableJSON(results), 0)
+	slices.SortStableFunc(locations, compareLocations)
+
+	var selected []interface{}
+	for _, location := range locations {
+		if value := location.First(results); value != nil {
+			selected = append(selected, value)
+		}
+	}
+	return selected
+}
+
+// filterableJSON copies decoded JSON with json.Number values converted to int64 or
+// float64, which JSONPath filters compare as numbers
+func filterableJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			copied[k] = filterableJSON(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = filterableJSON(item)
+		}
+		return copied
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+	}
+	return value
+}
+
+// compareLocations orders the locations of values in the order they are in the document
+func compareLocations(a, b jp.Expr) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		switch x := a[i].(type) {
+		case jp.Nth:
+			if y, ok := b[i].(jp.Nth); ok && x != y {
+				return int(x) - int(y)
+			}
+		case jp.Child:
+			if y, ok := b[i].(jp.Child); ok && x != y {
+				return strings.Compare(string(x), string(y))
+			}
+		}
+	}
+	return len(a) - len(b)
+}
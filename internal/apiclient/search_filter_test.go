@@ -0,0 +1,152 @@
+package apiclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const searchResults = `{"results":[
+	{"id":"1","name":"web","env":"dev","replicas":1},
+	{"id":"2","name":"web","env":"prod","replicas":3},
+	{"id":"3","name":"Worker","env":"prod","replicas":5}
+]}`
+
+// TestFindObjectWithOpts tests searching for objects with search filters
+func TestFindObjectWithOpts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(searchResults))
+	}))
+	defer server.Close()
+
+	client, err := NewAPIClient(&APIClientOpt{URI: server.URL, Timeout: 2, IDAttribute: "id"})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		search  SearchOpts
+		wantID  string
+		wantErr string
+	}{
+		"key_value": {
+			search: SearchOpts{SearchKey: "name", SearchValue: "web"},
+			wantID: "1",
+		},
+		"several_fields": {
+			search: SearchOpts{SearchFilter: "@.name == 'web' && @.env == 'prod'"},
+			wantID: "2",
+		},
+		"case_insensitive_prefix": {
+			search: SearchOpts{SearchFilter: "@.name =~ /(?i)^work/"},
+			wantID: "3",
+		},
+		"numeric": {
+			search: SearchOpts{SearchFilter: "@.replicas > 1 && @.replicas < 5"},
+			wantID: "2",
+		},
+		"full_path": {
+			search: SearchOpts{SearchFilter: "$[?(@.env == 'prod')]"},
+			wantID: "2",
+		},
+		"not_found": {
+			search:  SearchOpts{SearchFilter: "@.name == 'db'"},
+			wantErr: "failed to find an object matching search_filter '@.name == 'db''",
+		},
+		"invalid_filter": {
+			search:  SearchOpts{SearchFilter: "@.name == "},
+			wantErr: "invalid search_filter",
+		},
+		"filter_and_key": {
+			search:  SearchOpts{SearchFilter: "@.name == 'web'", SearchKey: "name", SearchValue: "web"},
+			wantErr: "cannot be combined",
+		},
+		"no_criteria": {
+			wantErr: "either search_filter or both search_key and search_value must be set",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			obj, err := NewAPIObject(client, &APIObjectOpts{Path: "/api/objects"})
+			require.NoError(t, err)
+
+			search := tc.search
+			search.ResultsKey = "results"
+			found, err := obj.FindObjectWithOpts(context.Background(), &search)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantID, obj.ID)
+			assert.Equal(t, tc.wantID, found["id"])
+		})
+	}
+}
+
+// TestReadSearchFilter tests reading an object with a read_search filter
+func TestReadSearchFilter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(searchResults))
+	}))
+	defer server.Close()
+
+	client, err := NewAPIClient(&APIClientOpt{URI: server.URL, Timeout: 2, IDAttribute: "id"})
+	require.NoError(t, err)
+
+	obj, err := NewAPIObject(client, &APIObjectOpts{
+		Path:       "/api/objects",
+		ID:         "3",
+		ReadSearch: map[string]string{"search_filter": "@.id == '{id}' && @.env == 'prod'", "results_key": "results"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, obj.ReadObject(context.Background()))
+	assert.Equal(t, "Worker", obj.GetApiData()["name"])
+
+	_, err = NewAPIObject(client, &APIObjectOpts{Path: "/api/objects", ReadSearch: map[string]string{"search_filter": "@.name =="}})
+	assert.ErrorContains(t, err, "invalid search_filter")
+}
+
+// TestReadSearchFilterEscapesID tests that the {id} substituted into a search_filter can't end its quoted string
+func TestReadSearchFilterEscapesID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results":[
+			{"id":"a","name":"decoy"},
+			{"id":"a'b)c","name":"quoted"},
+			{"id":"x\\\"y","name":"escaped"}
+		]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewAPIClient(&APIClientOpt{URI: server.URL, Timeout: 2, IDAttribute: "id"})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		id       string
+		wantName string
+	}{
+		"quote_and_paren":      {id: "a'b)c", wantName: "quoted"},
+		"backslash_and_dquote": {id: `x\"y`, wantName: "escaped"},
+		"injection":            {id: "none' || @.name == 'decoy"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			obj, err := NewAPIObject(client, &APIObjectOpts{
+				Path:       "/api/objects",
+				ID:         tc.id,
+				ReadSearch: map[string]string{"search_filter": "@.id == '{id}'", "results_key": "results"},
+			})
+			require.NoError(t, err)
+			require.NoError(t, obj.ReadObject(context.Background()))
+			if tc.wantName == "" {
+				assert.Empty(t, obj.ID, "An id that doesn't match should leave the object not found")
+				return
+			}
+			assert.Equal(t, tc.wantName, obj.GetApiData()["name"])
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package apiclient
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// secretFile is a secret (such as a token written by a Vault agent) kept in a file that may be
+// rotated while the provider runs. The file is read again whenever its modification time changes.
+type secretFile struct {
+	path    string
+	mux     sync.Mutex
+	modTime time.Time
+	size    int64
+	value   string
+}
+
+func newSecretFile(path string) *secretFile {
+	if path == "" {
+		return nil
+	}
+	return &secretFile{path: path}
+}
+
+// read returns the contents of the file without surrounding whitespace, re-reading it if it has changed
+func (f *secretFile) read(ctx context.Context) (string, error) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", fmt.Errorf("could not read secret file: %w", err)
+	}
+	if !f.modTime.IsZero() && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.value, nil
+	}
+
+	tflog.Debug(ctx, "Reading secret file", map[string]interface{}{"path": f.path, "mod_time": info.ModTime().String()})
+	b, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", fmt.Errorf("could not read secret file: %w", err)
+	}
+	f.value = strings.TrimSpace(string(b))
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+	return f.value, nil
+}
@@ -0,0 +1,99 @@
+package apiclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rotate rewrites a secret file, moving its modification time forward as a rotation would
+func rotate(t *testing.T, path string, value string, modTime time.Time) {
+	require.NoError(t, os.WriteFile(path, []byte(value), 0600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+// TestSecretFiles tests that secrets are read from files and re-read when the files change
+func TestSecretFiles(t *testing.T) {
+	var gotAuth, gotKey, gotUser, gotPassword string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotKey = r.Header.Get("X-Api-Key")
+		gotUser, gotPassword, _ = r.BasicAuth()
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	keyFile := filepath.Join(dir, "key")
+	passwordFile := filepath.Join(dir, "password")
+	start := time.Now().Add(-time.Hour)
+	rotate(t, tokenFile, "token-1\n", start)
+	rotate(t, keyFile, "key-1\n", start)
+	rotate(t, passwordFile, "password-1\n", start)
+
+	tokenClient, err := NewAPIClient(&APIClientOpt{
+		URI:             server.URL,
+		Timeout:         2,
+		BearerTokenFile: tokenFile,
+		Headers:         map[string]string{"X-Api-Key": "static"},
+		HeaderFiles:     map[string]string{"X-Api-Key": keyFile},
+	})
+	require.NoError(t, err)
+	basicClient, err := NewAPIClient(&APIClientOpt{
+		URI:          server.URL,
+		Timeout:      2,
+		Username:     "user",
+		PasswordFile: passwordFile,
+	})
+	require.NoError(t, err)
+
+	_, _, err = tokenClient.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token-1", gotAuth)
+	assert.Equal(t, "key-1", gotKey, "Header files should take precedence over headers")
+
+	_, _, err = basicClient.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+	require.NoError(t, err)
+	assert.Equal(t, "user", gotUser)
+	assert.Equal(t, "password-1", gotPassword)
+
+	rotate(t, tokenFile, "token-2\n", start.Add(time.Minute))
+	rotate(t, keyFile, "key-2\n", start.Add(time.Minute))
+	rotate(t, passwordFile, "password-2\n", start.Add(time.Minute))
+
+	_, _, err = tokenClient.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token-2", gotAuth)
+	assert.Equal(t, "key-2", gotKey)
+
+	_, _, err = basicClient.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+	require.NoError(t, err)
+	assert.Equal(t, "password-2", gotPassword)
+}
+
+// TestSecretFilesMissing tests that missing secret files are reported
+func TestSecretFilesMissing(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	_, err := NewAPIClient(&APIClientOpt{URI: "http://127.0.0.1:8083", BearerTokenFile: missing})
+	assert.ErrorContains(t, err, "could not read secret file")
+
+	_, err = NewAPIClient(&APIClientOpt{URI: "http://127.0.0.1:8083", HeaderFiles: map[string]string{"X-Api-Key": missing}})
+	assert.ErrorContains(t, err, "could not read secret file")
+
+	// A file removed after the client was set up fails the request
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	rotate(t, tokenFile, "token", time.Now())
+	client, err := NewAPIClient(&APIClientOpt{URI: "http://127.0.0.1:8083", Timeout: 2, BearerTokenFile: tokenFile})
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(tokenFile))
+	_, _, err = client.SendRequest(context.Background(), "GET", "/api/objects/1", "", false)
+	assert.ErrorContains(t, err, "could not read secret file")
+}
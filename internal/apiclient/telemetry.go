@@ -0,0 +1,135 @@
+package apiclient
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/Mastercard/terraform-provider-restapi/internal/apiclient"
+
+// telemetry holds the OpenTelemetry instruments used by the client. With no tracer or
+// meter provider configured (the default), all instruments are no-ops.
+type telemetry struct {
+	tracer          trace.Tracer
+	propagator      propagation.TextMapPropagator
+	requestDuration metric.Float64Histogram
+}
+
+type pathTemplateKey struct{}
+
+// newTelemetry builds the instruments for a client, using the global OpenTelemetry
+// providers unless others are set in the options
+func newTelemetry(opt *APIClientOpt) *telemetry {
+	tracerProvider := opt.TracerProvider
+	if tracerProvider == nil {
+		tracerProvider = otel.GetTracerProvider()
+	}
+	meterProvider := opt.MeterProvider
+	if meterProvider == nil {
+		meterProvider = otel.GetMeterProvider()
+	}
+
+	meter := meterProvider.Meter(instrumentationName)
+	requestDuration, err := meter.Float64Histogram(
+		"http.client.request.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of HTTP requests sent to the API, including retries and rate limit waits"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	return &telemetry{
+		tracer:          tracerProvider.Tracer(instrumentationName),
+		propagator:      propagation.TraceContext{},
+		requestDuration: requestDuration,
+	}
+}
+
+// withPathTemplate records the path template (such as `/api/objects/{id}`) a request is made
+// for, so spans and metrics can be grouped without the cardinality of the actual object IDs
+func withPathTemplate(ctx context.Context, template string) context.Context {
+	return context.WithValue(ctx, pathTemplateKey{}, template)
+}
+
+func pathTemplate(ctx context.Context, path string) string {
+	if template, ok := ctx.Value(pathTemplateKey{}).(string); ok && template != "" {
+		return template
+	}
+	return path
+}
+
+// startRequestSpan starts the client span covering a call to SendRequest
+func (t *telemetry) startRequestSpan(ctx context.Context, method string, path string) (context.Context, trace.Span) {
+	template := pathTemplate(ctx, path)
+	return t.tracer.Start(ctx, method+" "+template,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", method),
+			attribute.String("url.template", template),
+			attribute.String("url.path", path),
+		),
+	)
+}
+
+// endRequestSpan records the outcome of a call to SendRequest on its span and metrics
+func (t *telemetry) endRequestSpan(ctx context.Context, span trace.Span, method string, path string, status int, err error, duration time.Duration) {
+	attrs := []attribute.KeyValue{
+		attribute.String("http.request.method", method),
+		attribute.String("url.template", pathTemplate(ctx, path)),
+	}
+	if status != 0 {
+		attrs = append(attrs, attribute.Int("http.response.status_code", status))
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+	}
+
+	if err != nil {
+		errorType := "error"
+		if status >= 400 {
+			errorType = http.StatusText(status)
+		}
+		attrs = append(attrs, attribute.String("error.type", errorType))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	if t.requestDuration != nil {
+		t.requestDuration.Record(ctx, duration.Seconds(), metric.WithAttributes(attrs...))
+	}
+	span.End()
+}
+
+// startObjectSpan starts the span covering an APIObject operation
+func (t *telemetry) startObjectSpan(ctx context.Context, operation string, obj *APIObject) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, "restapi."+operation, trace.WithAttributes(
+		attribute.String("restapi.object.id", obj.ID),
+	))
+}
+
+// endSpan ends a span, recording err on it if set
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// recordRetry is used as the retry client's request hook to count retries on the request span
+func recordRetry(_ retryablehttp.Logger, req *http.Request, attempt int) {
+	if attempt == 0 {
+		return
+	}
+	span := trace.SpanFromContext(req.Context())
+	span.SetAttributes(attribute.Int("http.request.resend_count", attempt))
+	span.AddEvent("retry", trace.WithAttributes(attribute.Int("attempt", attempt)))
+}
@@ -0,0 +1,118 @@
+package apiclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+// TestTelemetrySpans tests that object operations and requests are traced and the trace context is propagated
+func TestTelemetrySpans(t *testing.T) {
+	var attempts int32
+	var traceparent atomic.Value
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent.Store(r.Header.Get("traceparent"))
+		// Fail the first attempt so a retry is recorded
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id":"1234","name":"foo"}`))
+	}))
+	defer server.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	client, err := NewAPIClient(&APIClientOpt{
+		URI:            server.URL,
+		Timeout:        2,
+		IDAttribute:    "id",
+		RetryMax:       1,
+		RetryWaitMin:   0,
+		RetryWaitMax:   0,
+		RateLimit:      100,
+		TracerProvider: tracerProvider,
+		MeterProvider:  meterProvider,
+	})
+	require.NoError(t, err)
+
+	obj, err := NewAPIObject(client, &APIObjectOpts{
+		Path: "/api/objects",
+		ID:   "1234",
+		Data: `{"id":"1234","name":"foo"}`,
+	})
+	require.NoError(t, err)
+	require.NoError(t, obj.ReadObject(context.Background()))
+
+	spans := exporter.GetSpans().Snapshots()
+	require.Len(t, spans, 2)
+
+	requestSpan, objectSpan := spans[0], spans[1]
+	assert.Equal(t, "restapi.ReadObject", objectSpan.Name())
+	assert.Equal(t, "GET /api/objects/{id}", requestSpan.Name())
+	assert.Equal(t, objectSpan.SpanContext().SpanID(), requestSpan.Parent().SpanID(), "Request span should be a child of the object span")
+
+	attrs := spanAttributes(requestSpan)
+	assert.Equal(t, "GET", attrs["http.request.method"].AsString())
+	assert.Equal(t, "/api/objects/{id}", attrs["url.template"].AsString())
+	assert.Equal(t, "/api/objects/1234", attrs["url.path"].AsString())
+	assert.Equal(t, int64(200), attrs["http.response.status_code"].AsInt64())
+	assert.Equal(t, int64(1), attrs["http.request.resend_count"].AsInt64())
+	assert.Contains(t, attrs, attribute.Key("restapi.rate_limit.wait_ms"))
+
+	tp, _ := traceparent.Load().(string)
+	assert.Contains(t, tp, requestSpan.SpanContext().TraceID().String(), "traceparent header should carry the trace ID")
+
+	var metrics metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &metrics))
+	require.Len(t, metrics.ScopeMetrics, 1)
+	require.Len(t, metrics.ScopeMetrics[0].Metrics, 1)
+	assert.Equal(t, "http.client.request.duration", metrics.ScopeMetrics[0].Metrics[0].Name)
+}
+
+// TestTelemetrySpanErrors tests that failed requests mark their spans as errors
+func TestTelemetrySpanErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	client, err := NewAPIClient(&APIClientOpt{
+		URI:            server.URL,
+		Timeout:        2,
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)),
+	})
+	require.NoError(t, err)
+
+	_, _, err = client.SendRequest(context.Background(), "POST", "/api/objects", `{}`, false)
+	require.Error(t, err)
+
+	spans := exporter.GetSpans().Snapshots()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, int64(400), spanAttributes(spans[0])["http.response.status_code"].AsInt64())
+}
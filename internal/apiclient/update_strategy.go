@@ -0,0 +1,188 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+// Update strategies decide what is sent to the API to update an object
+const (
+	UpdateStrategyFull          = "full"           // The full data
+	UpdateStrategyMergePatch    = "merge_patch"    // An RFC 7396 JSON Merge Patch from the prior data to the data
+	UpdateStrategyJSONPatch     = "json_patch"     // An RFC 6902 JSON Patch from the prior data to the data
+	UpdateStrategyChangedFields = "changed_fields" // The top level fields that differ from the prior data
+)
+
+// UpdateStrategies lists the valid update strategies
+var UpdateStrategies = []string{UpdateStrategyFull, UpdateStrategyMergePatch, UpdateStrategyJSONPatch, UpdateStrategyChangedFields}
+
+// updatePayload builds the body of an update request according to the update strategy. It
+// returns the body, its content type (empty for the default) and whether anything changed.
+// Without prior data to compare against, the full data is sent. Whatever is sent is in the
+// request envelope, if there is one. Data that is not a JSON object is replaced as a whole.
+func (obj *APIObject) updatePayload() (string, string, bool, error) {
+	// Both sides are compared as they are sent to the API
+	data, err := obj.writeData(obj.data)
+	if err != nil {
+		return "", "", false, err
+	}
+	planned, err := json.Marshal(obj.wrapRequest(data))
+	if err != nil {
+		return "", "", false, err
+	}
+	if obj.updateStrategy == "" || obj.updateStrategy == UpdateStrategyFull || obj.priorData == nil {
+		return string(planned), "", true, nil
+	}
+
+	priorData, err := obj.writeData(obj.priorData)
+	if err != nil {
+		return "", "", false, err
+	}
+	prior, err := json.Marshal(obj.wrapRequest(priorData))
+	if err != nil {
+		return "", "", false, err
+	}
+
+	dataObject, dataIsObject := data.(map[string]interface{})
+	priorObject, priorIsObject := priorData.(map[string]interface{})
+
+	switch obj.updateStrategy {
+	case UpdateStrategyMergePatch:
+		// A merge patch of anything but an object is the new document
+		if !dataIsObject || !priorIsObject {
+			return string(planned), "application/merge-patch+json", !JSONEqual(priorData, data), nil
+		}
+		patch, err := jsonpatch.CreateMergePatch(prior, planned)
+		if err != nil {
+			return "", "", false, fmt.Errorf("failed to create merge patch: %w", err)
+		}
+		return string(patch), "application/merge-patch+json", string(patch) != "{}", nil
+
+	case UpdateStrategyJSONPatch:
+		ops := createJSONPatch("", obj.wrapRequest(priorData), obj.wrapRequest(data))
+		patch, err := json.Marshal(ops)
+		if err != nil {
+			return "", "", false, err
+		}
+		return string(patch), "application/json-patch+json", len(ops) > 0, nil
+
+	case UpdateStrategyChangedFields:
+		// Without fields to compare, the full data is sent
+		if !dataIsObject || !priorIsObject {
+			return string(planned), "", !JSONEqual(priorData, data), nil
+		}
+		changed := map[string]interface{}{}
+		for k, v := range dataObject {
+			if prev, ok := priorObject[k]; !ok || !JSONEqual(prev, v) {
+				changed[k] = v
+			}
+		}
+		// Fields no longer in the data are cleared
+		for k := range priorObject {
+			if _, ok := dataObject[k]; !ok {
+				changed[k] = nil
+			}
+		}
+		b, err := json.Marshal(obj.wrapRequest(changed))
+		if err != nil {
+			return "", "", false, err
+		}
+		return string(b), "", len(changed) > 0, nil
+	}
+
+	return "", "", false, fmt.Errorf("unknown update_strategy '%s'", obj.updateStrategy)
+}
+
+// RestrictToKeys returns a copy of document with only the keys found in any of the shapes,
+// in nested objects as well. Update strategies other than full compare the prior data with
+// the data, so restricting the prior data to the keys that are managed keeps fields that
+// only the API sets (such as an id or a timestamp) from being sent as removed. Values that
+// are not objects are returned as they are.
+func RestrictToKeys(document interface{}, shapes ...interface{}) interface{} {
+	object, ok := document.(map[string]interface{})
+	if !ok {
+		return document
+	}
+
+	var shapeObjects []map[string]interface{}
+	for _, shape := range shapes {
+		if shapeObject, ok := shape.(map[string]interface{}); ok {
+			shapeObjects = append(shapeObjects, shapeObject)
+		}
+	}
+	if len(shapeObjects) == 0 {
+		return document
+	}
+
+	restricted := map[string]interface{}{}
+	for k, v := range object {
+		var subShapes []interface{}
+		for _, shapeObject := range shapeObjects {
+			if subShape, ok := shapeObject[k]; ok {
+				subShapes = append(subShapes, subShape)
+			}
+		}
+		if len(subShapes) > 0 {
+			restricted[k] = RestrictToKeys(v, subShapes...)
+		}
+	}
+	return restricted
+}
+
+// jsonPatchOp is a single RFC 6902 operation. Value is raw JSON so that a null value is still sent.
+type jsonPatchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// rawJSON encodes a value parsed from JSON, which can't fail
+func rawJSON(v interface{}) json.RawMessage {
+	b, _ := json.Marshal(v)
+	return b
+}
+
+// createJSONPatch returns the operations that turn from into to. Objects are compared key by
+// key; any other values (including arrays) that differ are replaced as a whole.
+func createJSONPatch(path string, fromValue interface{}, toValue interface{}) []jsonPatchOp {
+	from, fromIsObj := fromValue.(map[string]interface{})
+	to, toIsObj := toValue.(map[string]interface{})
+	if !fromIsObj || !toIsObj {
+		if JSONEqual(fromValue, toValue) {
+			return nil
+		}
+		return []jsonPatchOp{{Op: "replace", Path: path, Value: rawJSON(toValue)}}
+	}
+
+	var ops []jsonPatchOp
+
+	// Sort keys so the patch is deterministic
+	keys := make([]string, 0, len(from)+len(to))
+	for k := range from {
+		keys = append(keys, k)
+	}
+	for k := range to {
+		if _, ok := from[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		p := path + "/" + escapeJSONPointer(k)
+		fromVal, inFrom := from[k]
+		toVal, inTo := to[k]
+		switch {
+		case !inTo:
+			ops = append(ops, jsonPatchOp{Op: "remove", Path: p})
+		case !inFrom:
+			ops = append(ops, jsonPatchOp{Op: "add", Path: p, Value: rawJSON(toVal)})
+		default:
+			ops = append(ops, createJSONPatch(p, fromVal, toVal)...)
+		}
+	}
+	return ops
+}
@@ -0,0 +1,187 @@
+package apiclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUpdatePayload tests the update body and content type of each update strategy
+func TestUpdatePayload(t *testing.T) {
+	client, err := NewAPIClient(&APIClientOpt{URI: "http://127.0.0.1:8083", Timeout: 2})
+	require.NoError(t, err)
+
+	prior := `{"id":"1","name":"foo","tags":["a"],"config":{"size":1,"color":"red"},"old":true}`
+	planned := `{"id":"1","name":"bar","tags":["a","b"],"config":{"size":2,"color":"red"},"new":null}`
+
+	tests := map[string]struct {
+		strategy        string
+		noPrior         bool
+		wantBody        string
+		wantContentType string
+	}{
+		"default": {
+			strategy: "",
+			wantBody: planned,
+		},
+		"full": {
+			strategy: UpdateStrategyFull,
+			wantBody: planned,
+		},
+		"merge_patch": {
+			strategy:        UpdateStrategyMergePatch,
+			wantBody:        `{"config":{"size":2},"name":"bar","new":null,"old":null,"tags":["a","b"]}`,
+			wantContentType: "application/merge-patch+json",
+		},
+		"json_patch": {
+			strategy: UpdateStrategyJSONPatch,
+			wantBody: `[
+				{"op":"replace","path":"/config/size","value":2},
+				{"op":"replace","path":"/name","value":"bar"},
+				{"op":"add","path":"/new","value":null},
+				{"op":"remove","path":"/old"},
+				{"op":"replace","path":"/tags","value":["a","b"]}
+			]`,
+			wantContentType: "application/json-patch+json",
+		},
+		"changed_fields": {
+			strategy: UpdateStrategyChangedFields,
+			wantBody: `{"config":{"size":2,"color":"red"},"name":"bar","new":null,"old":null,"tags":["a","b"]}`,
+		},
+		"no_prior_data": {
+			strategy: UpdateStrategyMergePatch,
+			noPrior:  true,
+			wantBody: planned,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			obj, err := NewAPIObject(client, &APIObjectOpts{Path: "/api/objects", Data: planned, UpdateStrategy: tc.strategy})
+			require.NoError(t, err)
+			if !tc.noPrior {
+				require.NoError(t, obj.SetPriorData(prior))
+			}
+
+			body, contentType, changed, err := obj.updatePayload()
+			require.NoError(t, err)
+			assert.True(t, changed)
+			assert.JSONEq(t, tc.wantBody, body)
+			assert.Equal(t, tc.wantContentType, contentType)
+		})
+	}
+}
+
+// TestCreateJSONPatch tests that generated JSON Patches turn the prior data into the planned data
+func TestCreateJSONPatch(t *testing.T) {
+	tests := map[string]struct {
+		prior   string
+		planned string
+	}{
+		"unchanged":      {prior: `{"a":1}`, planned: `{"a":1}`},
+		"nested":         {prior: `{"a":{"b":{"c":1,"d":2}}}`, planned: `{"a":{"b":{"c":3},"e":[1]}}`},
+		"type_change":    {prior: `{"a":{"b":1}}`, planned: `{"a":"b"}`},
+		"escaped_keys":   {prior: `{"a/b":1,"c~d":2}`, planned: `{"a/b":2}`},
+		"array_to_value": {prior: `{"a":[1,2]}`, planned: `{"a":null}`},
+	}
+
+	client, err := NewAPIClient(&APIClientOpt{URI: "http://127.0.0.1:8083", Timeout: 2})
+	require.NoError(t, err)
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			obj, err := NewAPIObject(client, &APIObjectOpts{Path: "/api/objects", ID: "1", Data: tc.planned, UpdateStrategy: UpdateStrategyJSONPatch})
+			require.NoError(t, err)
+			require.NoError(t, obj.SetPriorData(tc.prior))
+
+			body, _, changed, err := obj.updatePayload()
+			require.NoError(t, err)
+			assert.Equal(t, tc.prior != tc.planned, changed)
+			if !changed {
+				return
+			}
+
+			patch, err := jsonpatch.DecodePatch([]byte(body))
+			require.NoError(t, err)
+			patched, err := patch.Apply([]byte(tc.prior))
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.planned, string(patched))
+		})
+	}
+}
+
+// TestUpdateObjectStrategy tests that patches are sent with their content type and skipped when nothing changed
+func TestUpdateObjectStrategy(t *testing.T) {
+	var patches []string
+	var contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			b, _ := io.ReadAll(r.Body)
+			patches = append(patches, string(b))
+			contentType = r.Header.Get("Content-Type")
+		}
+		w.Write([]byte(`{"id":"1","name":"bar"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewAPIClient(&APIClientOpt{
+		URI:     server.URL,
+		Timeout: 2,
+		Headers: map[string]string{"Content-Type": "application/vnd.api+json"},
+	})
+	require.NoError(t, err)
+
+	obj, err := NewAPIObject(client, &APIObjectOpts{
+		Path:           "/api/objects",
+		ID:             "1",
+		Data:           `{"id":"1","name":"bar"}`,
+		UpdateMethod:   "PATCH",
+		UpdateStrategy: UpdateStrategyMergePatch,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, obj.SetPriorData(`{"id":"1","name":"foo"}`))
+	require.NoError(t, obj.UpdateObject(context.Background()))
+	require.Len(t, patches, 1)
+	assert.JSONEq(t, `{"name":"bar"}`, patches[0])
+	assert.Equal(t, "application/merge-patch+json", contentType, "The patch content type should take precedence over the configured headers")
+
+	require.NoError(t, obj.SetPriorData(`{"id":"1","name":"bar"}`))
+	require.NoError(t, obj.UpdateObject(context.Background()))
+	assert.Len(t, patches, 1, "No request should be sent for an empty patch")
+
+	_, err = NewAPIObject(client, &APIObjectOpts{Path: "/api/objects", ID: "1", UpdateStrategy: "diff"})
+	assert.ErrorContains(t, err, "update_strategy 'diff' is not valid")
+}
+
+// TestRestrictToKeys tests that documents are restricted to the keys of the shapes
+func TestRestrictToKeys(t *testing.T) {
+	document := map[string]interface{}{
+		"id":       "1",
+		"name":     "foo",
+		"metadata": map[string]interface{}{"labels": "a", "created": "2024-01-01"},
+		"tags":     []interface{}{"red"},
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"name":     "foo",
+		"metadata": map[string]interface{}{"labels": "a"},
+	}, RestrictToKeys(document, map[string]interface{}{"name": "bar", "metadata": map[string]interface{}{"labels": "b"}}))
+
+	// Keys of any of the shapes are kept
+	assert.Equal(t, map[string]interface{}{"name": "foo", "tags": []interface{}{"red"}},
+		RestrictToKeys(document, map[string]interface{}{"name": "bar"}, map[string]interface{}{"tags": []interface{}{}}))
+
+	// A value that is not an object in the shape is kept as a whole
+	assert.Equal(t, map[string]interface{}{"metadata": document["metadata"]},
+		RestrictToKeys(document, map[string]interface{}{"metadata": "x"}))
+
+	assert.Equal(t, []interface{}{"a"}, RestrictToKeys([]interface{}{"a"}, map[string]interface{}{"a": 1}))
+	assert.Equal(t, document, RestrictToKeys(document))
+}
@@ -29,13 +29,19 @@ func ApplyJSONPatch(ctx context.Context, obj map[string]interface{}, patch jsonp
 	}
 
 	var patchedObj map[string]interface{}
-	if err := json.Unmarshal(patchedBytes, &patchedObj); err != nil {
+	if err := DecodeJSON(patchedBytes, &patchedObj); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal patched object: %w", err)
 	}
 
 	return patchedObj, nil
 }
 
+// asObject returns a decoded JSON value as an object, or nil if it is an array or a value
+func asObject(v interface{}) map[string]interface{} {
+	object, _ := v.(map[string]interface{})
+	return object
+}
+
 // GetStringAtKey uses GetObjectAtKey to verify the resulting object is either a JSON string or Number and returns it as a string
 func GetStringAtKey(ctx context.Context, data map[string]interface{}, path string) (string, error) {
 	res, err := GetObjectAtKey(ctx, data, path)
@@ -47,6 +53,8 @@ func GetStringAtKey(ctx context.Context, data map[string]interface{}, path strin
 	switch tmp := res.(type) {
 	case string:
 		return tmp, nil
+	case json.Number:
+		return tmp.String(), nil
 	case float64:
 		return strconv.FormatFloat(tmp, 'f', -1, 64), nil
 	case bool:
@@ -73,7 +81,15 @@ func GetStringAtKey(ctx context.Context, data map[string]interface{}, path strin
 // Result:
 // attrs/id => 1234
 // config/foo => "abc"
+//
+// A path starting with a slash is a JSON Pointer (RFC 6901), such as /attrs/id, which
+// also indexes arrays and escapes keys containing / as ~1 and ~ as ~0.
 func GetObjectAtKey(ctx context.Context, data map[string]interface{}, path string) (interface{}, error) {
+	if IsJSONPointer(path) {
+		tflog.Debug(ctx, "GetObjectAtKey: Resolving JSON pointer", map[string]interface{}{"pointer": path})
+		return GetObjectAtPointer(data, path)
+	}
+
 	hash := data
 
 	parts := strings.Split(path, "/")
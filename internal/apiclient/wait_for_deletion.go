@@ -0,0 +1,109 @@
+package apiclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// DefaultDeletionPollInterval is how often an object is checked while waiting for its deletion
+const DefaultDeletionPollInterval = 5 * time.Second
+
+// WaitForDeletion configures DeleteObject to poll until the API no longer returns the object,
+// for APIs that delete objects asynchronously
+type WaitForDeletion struct {
+	Interval   time.Duration // Time between checks (defaults to DefaultDeletionPollInterval)
+	Timeout    time.Duration // How long to wait at most (0 waits as long as the context allows)
+	StateKey   string        // Key of a field whose value is StateValue once the object is deleted
+	StateValue string
+}
+
+// waitForDeletion polls the object until it is gone
+func (obj *APIObject) waitForDeletion(ctx context.Context) error {
+	wait := obj.deletionWait
+	if wait.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, wait.Timeout)
+		defer cancel()
+	}
+
+	started := time.Now()
+	for {
+		deleted, err := obj.isDeleted(ctx)
+		if err != nil && ctx.Err() == nil {
+			return fmt.Errorf("failed to check whether the object was deleted: %w", err)
+		}
+		if deleted {
+			tflog.Debug(ctx, "Object is deleted", map[string]interface{}{"id": obj.ID, "waited": time.Since(started).String()})
+			return nil
+		}
+
+		tflog.Debug(ctx, "Waiting for object to be deleted", map[string]interface{}{"id": obj.ID, "interval": wait.Interval.String()})
+		timer := time.NewTimer(wait.Interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("timed out after %s waiting for the object with id '%s' to be deleted", time.Since(started).Round(time.Second), obj.ID)
+		case <-timer.C:
+		}
+	}
+}
+
+// isDeleted reports whether the object is gone: read_search no longer finds it, reading it
+// returns 404 or 410, or the state key has the deleted state value
+func (obj *APIObject) isDeleted(ctx context.Context) (bool, error) {
+	var found map[string]interface{}
+
+	if obj.hasReadSearch() {
+		// FindObject sets the id of what it finds, which must not change here
+		id := obj.ID
+		defer func() { obj.ID = id }()
+
+		var err error
+		found, err = obj.findByReadSearch(ctx)
+		if errors.Is(err, errObjectNotFound) || (err == nil && found == nil) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+	} else {
+		getPath := obj.readPath
+		if obj.queryString != "" {
+			getPath = fmt.Sprintf("%s?%s", obj.readPath, obj.queryString)
+		}
+
+		body, code, err := obj.sendRequest(ctx, obj.readPath, obj.readMethod, getPath, "")
+		if code == http.StatusNotFound || code == http.StatusGone {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if obj.deletionWait.StateKey == "" {
+			return false, nil
+		}
+		// state_key is relative to the object, as for objects found by read_search
+		if body, err = obj.unwrapResponse(ctx, body); err != nil {
+			return false, err
+		}
+		var document interface{}
+		if err := DecodeJSON([]byte(body), &document); err != nil {
+			return false, fmt.Errorf("failed to parse the object: %w", err)
+		}
+		found = asObject(document)
+	}
+
+	if obj.deletionWait.StateKey == "" {
+		return false, nil
+	}
+	state, err := GetStringAtKey(ctx, found, obj.deletionWait.StateKey)
+	if err != nil {
+		return false, nil
+	}
+	return state == obj.deletionWait.StateValue, nil
+}
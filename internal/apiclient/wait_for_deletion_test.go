@@ -0,0 +1,139 @@
+package apiclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// asyncDeleteServer is an API that only removes an object some reads after it is deleted
+type asyncDeleteServer struct {
+	mux sync.Mutex
+	// remaining is how many more reads still see the object after its deletion (-1 while not deleted)
+	remaining int
+	// gone is how a deleted object is reported: "404", "state" or "search"
+	gone  string
+	reads int
+}
+
+func (s *asyncDeleteServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if r.Method == http.MethodDelete {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	s.reads++
+	status := "ACTIVE"
+	if s.remaining >= 0 {
+		status = "DELETING"
+		if s.remaining == 0 {
+			status = "DELETED"
+		} else {
+			s.remaining--
+		}
+	}
+
+	switch {
+	case status == "DELETED" && s.gone == "404":
+		w.WriteHeader(http.StatusNotFound)
+	case r.URL.Path == "/api/objects" && status == "DELETED" && s.gone == "search":
+		w.Write([]byte(`[]`))
+	case r.URL.Path == "/api/objects":
+		fmt.Fprintf(w, `[{"id":"1","name":"foo","status":"%s"}]`, status)
+	default:
+		fmt.Fprintf(w, `{"id":"1","name":"foo","status":"%s"}`, status)
+	}
+}
+
+// TestWaitForDeletion tests that DeleteObject waits until the object is gone
+func TestWaitForDeletion(t *testing.T) {
+	tests := map[string]struct {
+		gone       string
+		remaining  int
+		wait       WaitForDeletion
+		readSearch map[string]string
+		wantReads  int
+		wantErr    string
+	}{
+		"not_found": {
+			gone:      "404",
+			remaining: 2,
+			wait:      WaitForDeletion{Interval: 10 * time.Millisecond},
+			wantReads: 3,
+		},
+		"state": {
+			gone:      "state",
+			remaining: 2,
+			wait:      WaitForDeletion{Interval: 10 * time.Millisecond, StateKey: "status", StateValue: "DELETED"},
+			wantReads: 3,
+		},
+		"read_search": {
+			gone:       "search",
+			remaining:  1,
+			wait:       WaitForDeletion{Interval: 10 * time.Millisecond},
+			readSearch: map[string]string{"search_key": "name", "search_value": "foo"},
+			wantReads:  2,
+		},
+		"read_search_state": {
+			gone:       "state",
+			remaining:  1,
+			wait:       WaitForDeletion{Interval: 10 * time.Millisecond, StateKey: "status", StateValue: "DELETED"},
+			readSearch: map[string]string{"search_key": "name", "search_value": "foo"},
+			wantReads:  2,
+		},
+		"timeout": {
+			gone:      "404",
+			remaining: 1000,
+			wait:      WaitForDeletion{Interval: 10 * time.Millisecond, Timeout: 50 * time.Millisecond},
+			wantErr:   "waiting for the object with id '1' to be deleted",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			api := &asyncDeleteServer{remaining: -1, gone: tc.gone}
+			server := httptest.NewServer(api)
+			defer server.Close()
+
+			client, err := NewAPIClient(&APIClientOpt{URI: server.URL, Timeout: 2, IDAttribute: "id"})
+			require.NoError(t, err)
+
+			wait := tc.wait
+			obj, err := NewAPIObject(client, &APIObjectOpts{
+				Path:            "/api/objects",
+				ID:              "1",
+				ReadSearch:      tc.readSearch,
+				WaitForDeletion: &wait,
+			})
+			require.NoError(t, err)
+
+			api.mux.Lock()
+			api.remaining = tc.remaining
+			api.mux.Unlock()
+
+			err = obj.DeleteObject(context.Background())
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantReads, api.reads)
+			assert.Equal(t, "1", obj.ID)
+		})
+	}
+
+	client, err := NewAPIClient(&APIClientOpt{URI: "http://127.0.0.1:8083", Timeout: 2})
+	require.NoError(t, err)
+	_, err = NewAPIObject(client, &APIObjectOpts{Path: "/api/objects", ID: "1", WaitForDeletion: &WaitForDeletion{StateKey: "status"}})
+	assert.ErrorContains(t, err, "state_key and state_value must be set together")
+}
@@ -8,31 +8,41 @@ import (
 
 	"github.com/Mastercard/terraform-provider-restapi/internal/apiclient"
 	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSourceWithValidateConfig = &RestAPIObjectDataSource{}
+
 type RestAPIObjectDataSource struct {
 	providerData *ProviderData
 }
 
 type RestAPIObjectDataSourceModel struct {
 	Path                  types.String         `tfsdk:"path"`
+	Endpoint              types.String         `tfsdk:"endpoint"`
 	SearchPath            types.String         `tfsdk:"search_path"`
 	QueryString           types.String         `tfsdk:"query_string"`
 	ReadQueryString       types.String         `tfsdk:"read_query_string"`
 	SearchData            jsontypes.Normalized `tfsdk:"search_data"`
 	SearchKey             types.String         `tfsdk:"search_key"`
 	SearchValue           types.String         `tfsdk:"search_value"`
+	SearchFilter          types.String         `tfsdk:"search_filter"`
+	MatchPolicy           types.String         `tfsdk:"match_policy"`
 	ResultsKey            types.String         `tfsdk:"results_key"`
 	ResultsContainsObject types.Bool           `tfsdk:"results_contains_object"`
 	IDAttribute           types.String         `tfsdk:"id_attribute"`
+	ReadPatch             jsontypes.Normalized `tfsdk:"read_patch"`
 	Debug                 types.Bool           `tfsdk:"debug"`
 	ID                    types.String         `tfsdk:"id"`
 	APIData               types.Map            `tfsdk:"api_data"`
 	APIResponse           types.String         `tfsdk:"api_response"`
+	Timeouts              timeouts.Value       `tfsdk:"timeouts"`
 }
 
 func NewRestAPIObjectDataSource() datasource.DataSource {
@@ -55,6 +65,10 @@ func (r *RestAPIObjectDataSource) Schema(ctx context.Context, req datasource.Sch
 				Description: "The API path on top of the base URL set in the provider that represents objects of this type on the API server.",
 				Required:    true,
 			},
+			"endpoint": schema.StringAttribute{
+				Description: "The name of one of the provider's `endpoints` to send requests to. If not set, the provider's `uri` is used.",
+				Optional:    true,
+			},
 			"search_path": schema.StringAttribute{
 				Description: "The API path on top of the base URL set in the provider that represents the location to search for objects of this type on the API server. If not set, defaults to the value of path.",
 				Optional:    true,
@@ -73,15 +87,23 @@ func (r *RestAPIObjectDataSource) Schema(ctx context.Context, req datasource.Sch
 				CustomType:  jsontypes.NormalizedType{},
 			},
 			"search_key": schema.StringAttribute{
-				Description: "When reading search results from the API, this key is used to identify the specific record to read. This should be a unique record such as 'name'. Similar to results_key, the value may be in the format of 'field/field/field' to search for data deeper in the returned object.",
-				Required:    true,
+				Description: "When reading search results from the API, this key is used to identify the specific record to read. This should be a unique record such as 'name'. Similar to results_key, the value may be in the format of 'field/field/field' to search for data deeper in the returned object, or a JSON Pointer such as '/meta/name'. Required unless `search_filter` is set.",
+				Optional:    true,
 			},
 			"search_value": schema.StringAttribute{
-				Description: "The value of 'search_key' will be compared to this value to determine if the correct object was found. Example: if 'search_key' is 'name' and 'search_value' is 'foo', the record in the array returned by the API with name=foo will be used.",
-				Required:    true,
+				Description: "The value of 'search_key' will be compared to this value to determine if the correct object was found. Example: if 'search_key' is 'name' and 'search_value' is 'foo', the record in the array returned by the API with name=foo will be used. Required unless `search_filter` is set.",
+				Optional:    true,
+			},
+			"search_filter": schema.StringAttribute{
+				Description: "A JSONPath filter expression the record to read must match, as an alternative to `search_key` and `search_value`. Example: `@.name == 'foo' && @.env =~ /(?i)^prod/`. Supports `==`, `!=`, `<`, `<=`, `>`, `>=`, `=~` (regular expressions, `(?i)` for case-insensitive), `&&`, `||` and nested fields (`@.meta.name`). A full JSONPath starting with `$`, applied to the results array, can be given instead.",
+				Optional:    true,
+			},
+			"match_policy": schema.StringAttribute{
+				Description: "Defaults to `first`. Which record to read when several match: `first` or `last` uses the first or last one in the results, and `unique` fails with the ids of the matching records, so that a name that isn't unique doesn't make Terraform manage the wrong object.",
+				Optional:    true,
 			},
 			"results_key": schema.StringAttribute{
-				Description: "When issuing a GET to the path, this JSON key is used to locate the results array. The format is 'field/field/field'. Example: 'results/values'. If omitted, it is assumed the results coming back are already an array and are to be used exactly as-is.",
+				Description: "When issuing a GET to the path, this JSON key is used to locate the results array. The format is 'field/field/field'. Example: 'results/values'. A JSON Pointer such as '/results/values' can also be used. If omitted, it is assumed the results coming back are already an array and are to be used exactly as-is.",
 				Optional:    true,
 			},
 			"results_contains_object": schema.BoolAttribute{
@@ -92,6 +114,11 @@ func (r *RestAPIObjectDataSource) Schema(ctx context.Context, req datasource.Sch
 				Description: "Defaults to `id_attribute` set on the provider. Allows per-resource override of `id_attribute` (see `id_attribute` provider config documentation)",
 				Optional:    true,
 			},
+			"read_patch": schema.StringAttribute{
+				Description: "A JSON Patch (RFC 6902) applied to the object found, to reshape it before it is set in `api_data` and `api_response`. The search itself (`search_key`, `results_key`) works on the unpatched results.",
+				Optional:    true,
+				CustomType:  jsontypes.NormalizedType{},
+			},
 			"debug": schema.BoolAttribute{
 				Description: "Whether to emit verbose debug output while working with the API object on the server.",
 				Optional:    true,
@@ -112,6 +139,11 @@ func (r *RestAPIObjectDataSource) Schema(ctx context.Context, req datasource.Sch
 				Computed:    true,
 			},
 		}, // End schema
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockWithOpts(ctx, timeouts.Opts{
+				ReadDescription: "Deadline for reading the object, including searching, retries and rate limit waits. A duration such as `30s` or `2h45m`. Defaults to no deadline.",
+			}),
+		},
 	}
 }
 
@@ -133,6 +165,33 @@ func (r *RestAPIObjectDataSource) Configure(ctx context.Context, req datasource.
 	r.providerData = providerData
 }
 
+// ValidateConfig checks that the object is searched for either with search_filter or
+// with search_key and search_value
+func (r *RestAPIObjectDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var config RestAPIObjectDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.SearchFilter.IsUnknown() || config.SearchKey.IsUnknown() || config.SearchValue.IsUnknown() {
+		return
+	}
+	if !config.SearchFilter.IsNull() {
+		if !config.SearchKey.IsNull() || !config.SearchValue.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("search_filter"), "Invalid Search Configuration",
+				"search_filter cannot be combined with search_key and search_value.")
+		}
+		return
+	}
+	for name, value := range map[string]types.String{"search_key": config.SearchKey, "search_value": config.SearchValue} {
+		if value.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root(name), "Invalid Search Configuration",
+				fmt.Sprintf("The argument \"%s\" is required unless search_filter is set.", name))
+		}
+	}
+}
+
 func (r *RestAPIObjectDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state RestAPIObjectDataSourceModel
 
@@ -142,6 +201,14 @@ func (r *RestAPIObjectDataSource) Read(ctx context.Context, req datasource.ReadR
 		return
 	}
 
+	timeout, diags := state.Timeouts.Read(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withOperationTimeout(ctx, timeout)
+	defer cancel()
+
 	queryString := existingOrDefaultString(state.QueryString, "")
 	searchKey := existingOrDefaultString(state.SearchKey, "")
 	searchValue := existingOrDefaultString(state.SearchValue, "")
@@ -162,10 +229,12 @@ func (r *RestAPIObjectDataSource) Read(ctx context.Context, req datasource.ReadR
 
 	opts := &apiclient.APIObjectOpts{
 		Path:        state.Path.ValueString(),
+		Endpoint:    existingOrDefaultString(state.Endpoint, ""),
 		SearchPath:  state.SearchPath.ValueString(),
 		Debug:       state.Debug.ValueBool(),
 		QueryString: queryString,
 		IDAttribute: existingOrProviderOrDefaultString(state.IDAttribute, client.Opts.IDAttribute, ""),
+		ReadPatch:   state.ReadPatch.ValueString(),
 	}
 
 	// If we have a read_query_string, we will use that in the API Object since the
@@ -188,11 +257,19 @@ func (r *RestAPIObjectDataSource) Read(ctx context.Context, req datasource.ReadR
 		return
 	}
 
-	foundData, err := obj.FindObject(ctx, queryString, searchKey, searchValue, resultsKey, send)
+	foundData, err := obj.FindObjectWithOpts(ctx, &apiclient.SearchOpts{
+		QueryString:  queryString,
+		SearchKey:    searchKey,
+		SearchValue:  searchValue,
+		SearchFilter: existingOrDefaultString(state.SearchFilter, ""),
+		ResultsKey:   resultsKey,
+		SearchData:   send,
+		MatchPolicy:  existingOrDefaultString(state.MatchPolicy, ""),
+	})
 	if err != nil {
 		tflog.Error(ctx, "Error finding API object", map[string]interface{}{"error": err})
 		resp.Diagnostics.AddError(
-			"Error Finding API Object",
+			apiErrorSummary("Error Finding API Object", err),
 			fmt.Sprintf("Could not find API object: %s", err.Error()),
 		)
 		return
@@ -215,7 +292,7 @@ func (r *RestAPIObjectDataSource) Read(ctx context.Context, req datasource.ReadR
 		if err != nil {
 			tflog.Error(ctx, "Error reading API object", map[string]interface{}{"error": err})
 			resp.Diagnostics.AddError(
-				"Error Reading API Object",
+				apiErrorSummary("Error Reading API Object", err),
 				fmt.Sprintf("Could not read API object: %s", err.Error()),
 			)
 			return
@@ -108,6 +108,54 @@ func TestDataSourceObject_valid(t *testing.T) {
 				search_key = "attributes/identifier/id"
 				search_value = "12345"
 			}`,
+
+		"with_search_filter": `
+			provider "restapi" {
+               	uri = "http://localhost:8080/"
+			}
+			data "restapi_object" "test" {
+				path = "/api/objects"
+				search_filter = "@.name == 'test' && @.env =~ /(?i)^prod/"
+			}`,
+
+		"with_match_policy": `
+			provider "restapi" {
+               	uri = "http://localhost:8080/"
+			}
+			data "restapi_object" "test" {
+				path = "/api/objects"
+				search_key = "name"
+				search_value = "test"
+				match_policy = "unique"
+			}`,
+
+		"with_endpoint": `
+			provider "restapi" {
+               	uri = "http://localhost:8080/"
+				endpoints = {
+					v2 = {
+						uri = "http://localhost:8080/v2"
+					}
+				}
+			}
+			data "restapi_object" "test" {
+				path = "/api/objects"
+				endpoint = "v2"
+				search_key = "name"
+				search_value = "test"
+			}`,
+		"with_timeouts": `
+			provider "restapi" {
+               	uri = "http://localhost:8080/"
+			}
+			data "restapi_object" "test" {
+				path = "/api/objects"
+				search_key = "name"
+				search_value = "test"
+				timeouts {
+					read = "2m"
+				}
+			}`,
 	}
 
 	for name, config := range tests {
@@ -157,7 +205,7 @@ func TestDataSourceObject_invalid(t *testing.T) {
 					search_value = "test"
 				}
 			`,
-			expectError: `The argument "search_key" is required`,
+			expectError: `The argument "search_key" is required unless search_filter is set`,
 		},
 
 		"missing_search_value": {
@@ -170,7 +218,21 @@ func TestDataSourceObject_invalid(t *testing.T) {
 					search_key = "name"
 				}
 			`,
-			expectError: `The argument "search_value" is required`,
+			expectError: `The argument "search_value" is required unless search_filter is set`,
+		},
+
+		"search_filter_and_search_key": {
+			config: `
+				provider "restapi" {
+					uri = "http://localhost:8080/"
+				}
+				data "restapi_object" "test" {
+					path = "/api/objects"
+					search_filter = "@.name == 'test'"
+					search_key = "name"
+				}
+			`,
+			expectError: `search_filter cannot be combined with search_key and search_value`,
 		},
 
 		"invalid_search_data_json": {
@@ -2,14 +2,38 @@ package provider
 
 import (
 	"reflect"
+	"strconv"
 	"strings"
+
+	"github.com/Mastercard/terraform-provider-restapi/internal/apiclient"
 )
 
-// getDelta performs a deep comparison of two maps - the resource as recorded in state, and the resource as returned by the API.
-// Accepts a third argument that is a set of fields that are to be ignored when looking for differences.
+// getDelta performs a deep comparison of two JSON documents - the resource as recorded in state, and the resource as returned by the API.
+// The documents are usually objects, but can be arrays or values.
+// Accepts a third argument that is a set of fields that are to be ignored when looking for differences, either in dot
+// syntax (metadata.timestamp) or as JSON Pointers (/metadata/timestamp, /tags/0).
 // Accepts a fourth argument ignoreServerAdditions - when true, fields added by the server (but not in recorded) will be ignored.
 // Returns 1. the recordedResource overlaid with fields that have been modified in actualResource but not ignored, and 2. a bool true if there were any changes.
-func getDelta(recorded map[string]interface{}, actual map[string]interface{}, ignoreList []string, ignoreServerAdditions bool) (modifiedResource map[string]interface{}, hasChanges bool) {
+func getDelta(recorded interface{}, actual interface{}, ignoreList []string, ignoreServerAdditions bool) (modifiedResource interface{}, hasChanges bool) {
+	return getValueDelta(recorded, actual, true, parseIgnoreList(ignoreList), ignoreServerAdditions)
+}
+
+// parseIgnoreList splits each field of an ignore list into its keys
+func parseIgnoreList(ignoreList []string) [][]string {
+	paths := make([][]string, 0, len(ignoreList))
+	for _, ignorePath := range ignoreList {
+		if apiclient.IsJSONPointer(ignorePath) {
+			if tokens, err := apiclient.ParseJSONPointer(ignorePath); err == nil {
+				paths = append(paths, tokens)
+				continue
+			}
+		}
+		paths = append(paths, strings.Split(ignorePath, "."))
+	}
+	return paths
+}
+
+func getMapDelta(recorded map[string]interface{}, actual map[string]interface{}, ignoreList [][]string, ignoreServerAdditions bool) (modifiedResource map[string]interface{}, hasChanges bool) {
 	modifiedResource = map[string]interface{}{}
 	hasChanges = false
 
@@ -21,53 +45,15 @@ func getDelta(recorded map[string]interface{}, actual map[string]interface{}, ig
 		checkedKeys[key] = struct{}{}
 
 		// If the ignore_list contains the current key, don't compare
-		if contains(ignoreList, key) {
+		if isIgnored(ignoreList, key) {
 			modifiedResource[key] = valRecorded
 			continue
 		}
 
 		valActual, actualHasKey := actual[key]
-
-		if valRecorded == nil {
-			// A JSON null was put in input data, confirm the result is either not set or is also null
-			modifiedResource[key] = valActual
-			if actualHasKey && valActual != nil {
-				hasChanges = true
-			}
-		} else if reflect.TypeOf(valRecorded).Kind() == reflect.Map {
-			// If valRecorded was a map, assert both values are maps
-			subMapA, okA := valRecorded.(map[string]interface{})
-			subMapB, okB := valActual.(map[string]interface{})
-			if !okA || !okB {
-				modifiedResource[key] = valActual
-				hasChanges = true
-				continue
-			}
-			// Recursively compare
-			deeperIgnoreList := _descendIgnoreList(key, ignoreList)
-			if modifiedSubResource, hasChange := getDelta(subMapA, subMapB, deeperIgnoreList, ignoreServerAdditions); hasChange {
-				modifiedResource[key] = modifiedSubResource
-				hasChanges = true
-			} else {
-				modifiedResource[key] = valRecorded
-			}
-		} else if reflect.TypeOf(valRecorded).Kind() == reflect.Slice {
-			// Since we don't support ignoring differences in lists (besides ignoring the list as a
-			// whole), it is safe to deep compare the two list values.
-			if !reflect.DeepEqual(valRecorded, valActual) {
-				modifiedResource[key] = valActual
-				hasChanges = true
-			} else {
-				modifiedResource[key] = valRecorded
-			}
-		} else if valRecorded != valActual {
-			modifiedResource[key] = valActual
-			hasChanges = true
-		} else {
-			// In this case, the recorded and actual values were the same
-			modifiedResource[key] = valRecorded
-		}
-
+		modifiedValue, changed := getValueDelta(valRecorded, valActual, actualHasKey, descendIgnoreList(key, ignoreList), ignoreServerAdditions)
+		modifiedResource[key] = modifiedValue
+		hasChanges = hasChanges || changed
 	}
 
 	for key, valActual := range actual {
@@ -79,7 +65,7 @@ func getDelta(recorded map[string]interface{}, actual map[string]interface{}, ig
 
 		// If the ignore_list contains the current key, don't compare.
 		// Don't modify modifiedResource either - we don't want this key to be tracked
-		if contains(ignoreList, key) {
+		if isIgnored(ignoreList, key) {
 			continue
 		}
 
@@ -95,26 +81,82 @@ func getDelta(recorded map[string]interface{}, actual map[string]interface{}, ig
 	return modifiedResource, hasChanges
 }
 
-// _descendIgnoreList modifies an ignoreList to be relative to a descended path.
-// E.g. given descendPath = "bar", and the ignoreList [foo, bar.alpha, bar.bravo], this returns [alpha, bravo]
-func _descendIgnoreList(descendPath string, ignoreList []string) []string {
-	newIgnoreList := make([]string, len(ignoreList))
+// getValueDelta compares a recorded value with the actual one, given the ignore list relative to it
+func getValueDelta(valRecorded interface{}, valActual interface{}, actualHasKey bool, ignoreList [][]string, ignoreServerAdditions bool) (interface{}, bool) {
+	if valRecorded == nil {
+		// A JSON null was put in input data, confirm the result is either not set or is also null
+		return valActual, actualHasKey && valActual != nil
+	}
 
-	for _, ignorePath := range ignoreList {
-		pathComponents := strings.Split(ignorePath, ".")
-		// If this ignorePath starts with the descendPath, remove the first component and keep the rest
-		if pathComponents[0] == descendPath {
-			modifiedPath := strings.Join(pathComponents[1:], ".")
-			newIgnoreList = append(newIgnoreList, modifiedPath)
+	switch reflect.TypeOf(valRecorded).Kind() {
+	case reflect.Map:
+		// If valRecorded was a map, assert both values are maps
+		subMapA, okA := valRecorded.(map[string]interface{})
+		subMapB, okB := valActual.(map[string]interface{})
+		if !okA || !okB {
+			return valActual, true
+		}
+		// Recursively compare
+		if modifiedSubResource, hasChange := getMapDelta(subMapA, subMapB, ignoreList, ignoreServerAdditions); hasChange {
+			return modifiedSubResource, true
+		}
+		return valRecorded, false
+	case reflect.Slice:
+		// Lists are compared element by element only when fields in them are ignored;
+		// otherwise it is safe to deep compare the two list values
+		listA, okA := valRecorded.([]interface{})
+		listB, okB := valActual.([]interface{})
+		if len(ignoreList) > 0 && okA && okB && len(listA) == len(listB) {
+			return getSliceDelta(listA, listB, ignoreList, ignoreServerAdditions)
+		}
+		if !apiclient.JSONEqual(valRecorded, valActual) {
+			return valActual, true
+		}
+		return valRecorded, false
+	}
+
+	// Numbers are compared by value, however they are written
+	if !apiclient.JSONEqual(valRecorded, valActual) {
+		return valActual, true
+	}
+	// In this case, the recorded and actual values were the same
+	return valRecorded, false
+}
+
+// getSliceDelta compares two lists of the same length element by element, with their
+// indexes as the keys of the ignore list
+func getSliceDelta(recorded []interface{}, actual []interface{}, ignoreList [][]string, ignoreServerAdditions bool) ([]interface{}, bool) {
+	modified := make([]interface{}, len(recorded))
+	hasChanges := false
+	for i := range recorded {
+		key := strconv.Itoa(i)
+		if isIgnored(ignoreList, key) {
+			modified[i] = recorded[i]
+			continue
 		}
+		var changed bool
+		modified[i], changed = getValueDelta(recorded[i], actual[i], true, descendIgnoreList(key, ignoreList), ignoreServerAdditions)
+		hasChanges = hasChanges || changed
 	}
+	return modified, hasChanges
+}
 
-	return newIgnoreList
+// descendIgnoreList makes an ignore list relative to a descended key.
+// E.g. given key = "bar", and the ignore list [foo, bar.alpha, bar.bravo], this returns [alpha, bravo]
+func descendIgnoreList(key string, ignoreList [][]string) [][]string {
+	var deeper [][]string
+	for _, ignorePath := range ignoreList {
+		if len(ignorePath) > 1 && ignorePath[0] == key {
+			deeper = append(deeper, ignorePath[1:])
+		}
+	}
+	return deeper
 }
 
-func contains(list []string, elem string) bool {
-	for _, a := range list {
-		if a == elem {
+// isIgnored reports whether the ignore list contains the key itself
+func isIgnored(ignoreList [][]string, key string) bool {
+	for _, ignorePath := range ignoreList {
+		if len(ignorePath) == 1 && ignorePath[0] == key {
 			return true
 		}
 	}
@@ -63,3 +63,52 @@ func TestAccRestApiObject_importBasic(t *testing.T) {
 		},
 	})
 }
+
+func TestAccRestApiObject_importEndpoint(t *testing.T) {
+	ctx := context.Background()
+	debug := false
+	apiServerObjects := make(map[string]map[string]interface{})
+
+	// The object only exists behind the endpoint; nothing listens on the provider's uri
+	svr := fakeserver.NewFakeServer(8130, apiServerObjects, map[string]string{}, true, debug, "")
+	defer svr.Shutdown()
+
+	client, err := apiclient.NewAPIClient(&apiclient.APIClientOpt{URI: "http://127.0.0.1:8130/", Timeout: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SendRequest(ctx, "POST", "/api/objects", `{ "id": "1234", "first": "Foo", "last": "Bar" }`, debug)
+
+	resource.UnitTest(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { svr.StartInBackground() },
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "restapi" {
+  uri = "http://127.0.0.1:8131"
+  endpoints = {
+    eu = {
+      uri = "http://127.0.0.1:8130"
+    }
+  }
+}
+
+resource "restapi_object" "Foo" {
+  path     = "/api/objects"
+  endpoint = "eu"
+  data     = jsonencode({ id = "1234", first = "Foo", last = "Bar" })
+}
+`,
+			},
+			{
+				ResourceName:            "restapi_object.Foo",
+				ImportState:             true,
+				ImportStateId:           `{"path": "/api/objects", "id": "1234", "endpoint": "eu"}`,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"debug", "data", "create_response", "ignore_all_server_changes", "ignore_server_additions", "object_id"},
+			},
+		},
+	})
+}
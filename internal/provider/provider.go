@@ -26,34 +26,57 @@ type RestAPIProvider struct {
 }
 
 type RestAPIProviderModel struct {
-	URI                 types.String          `tfsdk:"uri"`
-	Insecure            types.Bool            `tfsdk:"insecure"`
-	Username            types.String          `tfsdk:"username"`
-	Password            types.String          `tfsdk:"password"`
-	BearerToken         types.String          `tfsdk:"bearer_token"`
-	Headers             types.Map             `tfsdk:"headers"`
-	UseCookies          types.Bool            `tfsdk:"use_cookies"`
-	Timeout             types.Int64           `tfsdk:"timeout"`
-	IDAttribute         types.String          `tfsdk:"id_attribute"`
-	CreateMethod        types.String          `tfsdk:"create_method"`
-	ReadMethod          types.String          `tfsdk:"read_method"`
-	UpdateMethod        types.String          `tfsdk:"update_method"`
-	DestroyMethod       types.String          `tfsdk:"destroy_method"`
-	CopyKeys            types.List            `tfsdk:"copy_keys"`
-	WriteReturnsObject  types.Bool            `tfsdk:"write_returns_object"`
-	CreateReturnsObject types.Bool            `tfsdk:"create_returns_object"`
-	XSSIPrefix          types.String          `tfsdk:"xssi_prefix"`
-	RateLimit           types.Float64         `tfsdk:"rate_limit"`
-	TestPath            types.String          `tfsdk:"test_path"`
-	Debug               types.Bool            `tfsdk:"debug"`
-	CertString          types.String          `tfsdk:"cert_string"`
-	KeyString           types.String          `tfsdk:"key_string"`
-	CertFile            types.String          `tfsdk:"cert_file"`
-	KeyFile             types.String          `tfsdk:"key_file"`
-	RootCAFile          types.String          `tfsdk:"root_ca_file"`
-	RootCAString        types.String          `tfsdk:"root_ca_string"`
-	OAuthClientCreds    *OAuthClientDataModel `tfsdk:"oauth_client_credentials"`
-	RetriesConfig       *RetriesDataModel     `tfsdk:"retries"`
+	URI                 types.String             `tfsdk:"uri"`
+	URIs                types.List               `tfsdk:"uris"`
+	Endpoints           map[string]EndpointModel `tfsdk:"endpoints"`
+	Insecure            types.Bool               `tfsdk:"insecure"`
+	Username            types.String             `tfsdk:"username"`
+	Password            types.String             `tfsdk:"password"`
+	PasswordFile        types.String             `tfsdk:"password_file"`
+	BearerToken         types.String             `tfsdk:"bearer_token"`
+	BearerTokenFile     types.String             `tfsdk:"bearer_token_file"`
+	Headers             types.Map                `tfsdk:"headers"`
+	HeaderFiles         types.Map                `tfsdk:"header_files"`
+	UseCookies          types.Bool               `tfsdk:"use_cookies"`
+	CookieFile          types.String             `tfsdk:"cookie_file"`
+	Timeout             types.Int64              `tfsdk:"timeout"`
+	IDAttribute         types.String             `tfsdk:"id_attribute"`
+	CreateMethod        types.String             `tfsdk:"create_method"`
+	ReadMethod          types.String             `tfsdk:"read_method"`
+	UpdateMethod        types.String             `tfsdk:"update_method"`
+	DestroyMethod       types.String             `tfsdk:"destroy_method"`
+	CopyKeys            types.List               `tfsdk:"copy_keys"`
+	PathVariables       types.Map                `tfsdk:"path_variables"`
+	WriteReturnsObject  types.Bool               `tfsdk:"write_returns_object"`
+	CreateReturnsObject types.Bool               `tfsdk:"create_returns_object"`
+	ResponseEnvelopeKey types.String             `tfsdk:"response_envelope_key"`
+	RequestEnvelopeKey  types.String             `tfsdk:"request_envelope_key"`
+	XSSIPrefix          types.String             `tfsdk:"xssi_prefix"`
+	RequestIDHeader     types.String             `tfsdk:"request_id_header"`
+	RateLimit           types.Float64            `tfsdk:"rate_limit"`
+	AdaptiveRateLimit   types.Bool               `tfsdk:"adaptive_rate_limit"`
+	TestPath            types.String             `tfsdk:"test_path"`
+	Debug               types.Bool               `tfsdk:"debug"`
+	CertString          types.String             `tfsdk:"cert_string"`
+	KeyString           types.String             `tfsdk:"key_string"`
+	CertFile            types.String             `tfsdk:"cert_file"`
+	KeyFile             types.String             `tfsdk:"key_file"`
+	RootCAFile          types.String             `tfsdk:"root_ca_file"`
+	RootCAString        types.String             `tfsdk:"root_ca_string"`
+	OAuthClientCreds    *OAuthClientDataModel    `tfsdk:"oauth_client_credentials"`
+	CredentialProcess   *CredentialProcessModel  `tfsdk:"credential_process"`
+	CSRF                *CSRFDataModel           `tfsdk:"csrf"`
+	RetriesConfig       *RetriesDataModel        `tfsdk:"retries"`
+	FailoverStatusCodes types.List               `tfsdk:"failover_status_codes"`
+	RateLimitBudgets    []RateLimitBudgetModel   `tfsdk:"rate_limit_budget"`
+	MaxConcurrent       *MaxConcurrentDataModel  `tfsdk:"max_concurrent_requests"`
+	CircuitBreaker      *CircuitBreakerDataModel `tfsdk:"circuit_breaker"`
+}
+
+type EndpointModel struct {
+	URI     types.String `tfsdk:"uri"`
+	URIs    types.List   `tfsdk:"uris"`
+	Headers types.Map    `tfsdk:"headers"`
 }
 
 type OAuthClientDataModel struct {
@@ -64,12 +87,42 @@ type OAuthClientDataModel struct {
 	EndpointParams     types.Map    `tfsdk:"endpoint_params"`
 }
 
+type CredentialProcessModel struct {
+	Command types.String `tfsdk:"command"`
+	Args    types.List   `tfsdk:"args"`
+	Env     types.Map    `tfsdk:"env"`
+}
+
+type CSRFDataModel struct {
+	CookieName types.String `tfsdk:"cookie_name"`
+	TokenPath  types.String `tfsdk:"token_path"`
+	TokenKey   types.String `tfsdk:"token_key"`
+	HeaderName types.String `tfsdk:"header_name"`
+}
+
 type RetriesDataModel struct {
 	MaxRetries types.Int64 `tfsdk:"max_retries"`
 	MinWait    types.Int64 `tfsdk:"min_wait"`
 	MaxWait    types.Int64 `tfsdk:"max_wait"`
 }
 
+type RateLimitBudgetModel struct {
+	Method     types.String  `tfsdk:"method"`
+	PathPrefix types.String  `tfsdk:"path_prefix"`
+	RateLimit  types.Float64 `tfsdk:"rate_limit"`
+}
+
+type MaxConcurrentDataModel struct {
+	Total  types.Int64 `tfsdk:"total"`
+	Reads  types.Int64 `tfsdk:"reads"`
+	Writes types.Int64 `tfsdk:"writes"`
+}
+
+type CircuitBreakerDataModel struct {
+	FailureThreshold types.Int64 `tfsdk:"failure_threshold"`
+	Cooldown         types.Int64 `tfsdk:"cooldown"`
+}
+
 type ProviderData struct {
 	client *apiclient.APIClient
 	opts   *apiclient.APIClientOpt
@@ -111,7 +164,17 @@ func (p *RestAPIProvider) Schema(ctx context.Context, req provider.SchemaRequest
 		Attributes: map[string]schema.Attribute{
 			"uri": schema.StringAttribute{
 				Optional:    true,
-				Description: "URI of the REST API endpoint. This serves as the base of all requests.",
+				Description: "URI of the REST API endpoint. This serves as the base of all requests. Conflicts with `uris`.",
+			},
+			"uris": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "An ordered list of URIs of the same API (such as an active and a passive region), used in place of `uri`: requests go to the first URI, and fail over to the next one in order on connection errors or a `failover_status_codes` response. A URI that can't be reached is not retried while there is another one to try. The URI that answers is used for the remaining requests. The `REST_API_URIS` environment variable takes a comma-separated list. Conflicts with `uri`.",
+			},
+			"failover_status_codes": schema.ListAttribute{
+				ElementType: types.Int64Type,
+				Optional:    true,
+				Description: "When `uris` lists several URIs, responses with these status codes (such as `502` or `503`, after any retries) cause the request to be sent to the next URI. Connection errors always cause a failover.",
 			},
 			"insecure": schema.BoolAttribute{
 				Optional:    true,
@@ -126,27 +189,66 @@ func (p *RestAPIProvider) Schema(ctx context.Context, req provider.SchemaRequest
 				Sensitive:   true,
 				Description: "When set, will use this password for BASIC auth to the API.",
 			},
+			"password_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "When set, the password for BASIC auth is read from this file. The file is read again whenever it changes, so a rotated password is picked up during a run. Conflicts with `password`.",
+			},
+			"endpoints": schema.MapNestedAttribute{
+				Optional:    true,
+				Description: "A map of named endpoints, each an alternative base URI (such as a regional or versioned API) that a `restapi_object` or data source can select with its `endpoint` attribute. Requests to an endpoint share this provider's transport, authentication, rate limiting and other settings.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"uri": schema.StringAttribute{
+							Optional:    true,
+							Description: "Base URI of the endpoint, used in place of the provider's `uri`. Either `uri` or `uris` must be set.",
+						},
+						"uris": schema.ListAttribute{
+							ElementType: types.StringType,
+							Optional:    true,
+							Description: "Like the provider's `uris`, an ordered list of base URIs of the endpoint to fail over between. Either `uri` or `uris` must be set.",
+						},
+						"headers": schema.MapAttribute{
+							ElementType: types.StringType,
+							Optional:    true,
+							Description: "A map of header names and values to set on requests to this endpoint, in addition to (and overriding) the provider's `headers`.",
+						},
+					},
+				},
+			},
 			"bearer_token": schema.StringAttribute{
 				Optional:    true,
 				Sensitive:   true,
 				Description: "Token to use for Authorization: Bearer <token>",
 			},
+			"bearer_token_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "When set, the token to use for Authorization: Bearer <token> is read from this file, such as a Vault agent sink or a Kubernetes projected service account token. The file is read again whenever it changes, so a rotated token is picked up during a run. Conflicts with `bearer_token`.",
+			},
 			"headers": schema.MapAttribute{
 				ElementType: types.StringType,
 				Optional:    true,
 				Description: "A map of header names and values to set on all outbound requests. This is useful if you want to use a script via the 'external' provider or provide a pre-approved token or change Content-Type from `application/json`. If `username` and `password` are set and Authorization is one of the headers defined here, the BASIC auth credentials are discarded.",
 			},
+			"header_files": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "A map of header names to files their values are read from, to set on all outbound requests. Each file is read again whenever it changes, so rotated secrets are picked up during a run. These take precedence over `headers`.",
+			},
 			"use_cookies": schema.BoolAttribute{
 				Optional:    true,
 				Description: "Enable cookie jar to persist session.",
 			},
+			"cookie_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "When set, cookies are kept in this file (created readable only by the current user) so a session persists between Terraform runs instead of re-authenticating every time. Implies `use_cookies`.",
+			},
 			"timeout": schema.Int64Attribute{
 				Optional:    true,
 				Description: "When set, will cause requests taking longer than this time (in seconds) to be aborted. Must be a positive integer.",
 			},
 			"id_attribute": schema.StringAttribute{
 				Optional:    true,
-				Description: "When set, this key will be used to operate on REST objects. For example, if the ID is set to 'name', changes to the API object will be to http://foo.com/bar/VALUE_OF_NAME. This value may also be a '/'-delimeted path to the id attribute if it is multple levels deep in the data (such as `attributes/id` in the case of an object `{ \"attributes\": { \"id\": 1234 }, \"config\": { \"name\": \"foo\", \"something\": \"bar\"}}`",
+				Description: "When set, this key will be used to operate on REST objects. For example, if the ID is set to 'name', changes to the API object will be to http://foo.com/bar/VALUE_OF_NAME. This value may also be a '/'-delimeted path to the id attribute if it is multple levels deep in the data (such as `attributes/id` in the case of an object `{ \"attributes\": { \"id\": 1234 }, \"config\": { \"name\": \"foo\", \"something\": \"bar\"}}`. A key starting with `/` is a JSON Pointer (RFC 6901) instead, such as `/metadata/name` or `/items/0/id`, which indexes arrays and escapes `/` in keys as `~1` and `~` as `~0`.",
 			},
 			"create_method": schema.StringAttribute{
 				Description: "Defaults to `POST`. The HTTP method used to CREATE objects of this type on the API server.",
@@ -167,7 +269,12 @@ func (p *RestAPIProvider) Schema(ctx context.Context, req provider.SchemaRequest
 			"copy_keys": schema.ListAttribute{
 				ElementType: types.StringType,
 				Optional:    true,
-				Description: "When set, any PUT to the API for an object will copy these keys from the data the provider has gathered about the object. This is useful if internal API information must also be provided with updates, such as the revision of the object.",
+				Description: "When set, any PUT to the API for an object will copy these keys from the data the provider has gathered about the object. This is useful if internal API information must also be provided with updates, such as the revision of the object. Nested keys can be given as JSON Pointers, such as `/metadata/revision`.",
+			},
+			"path_variables": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "A map of values for placeholders in object paths, such as `{tenant}` in `/tenants/{tenant}/users`. Placeholders are looked up in the object's `data`, then its `api_data`, then here.",
 			},
 			"write_returns_object": schema.BoolAttribute{
 				Optional:    true,
@@ -177,14 +284,30 @@ func (p *RestAPIProvider) Schema(ctx context.Context, req provider.SchemaRequest
 				Optional:    true,
 				Description: "Set this when the API returns the object created only on creation operations (POST). This is used by the provider to refresh internal data structures.",
 			},
+			"response_envelope_key": schema.StringAttribute{
+				Optional:    true,
+				Description: "When the API wraps single objects in an envelope, such as `{\"data\": {...}, \"meta\": {...}}`, the path of the object in responses to create, read and update requests (`data` here). Like `results_key`, it is a path of keys separated by slashes or a JSON Pointer. Search results are not unwrapped. Can be overridden per object.",
+			},
+			"request_envelope_key": schema.StringAttribute{
+				Optional:    true,
+				Description: "When the API expects objects wrapped in an envelope on write, such as `{\"item\": {...}}`, the path objects are nested under in create and update requests (`item` here). Like `results_key`, it is a path of keys separated by slashes or a JSON Pointer. Can be overridden per object.",
+			},
 			"xssi_prefix": schema.StringAttribute{
 				Optional:    true,
 				Description: "Trim the xssi prefix from response string, if present, before parsing.",
 			},
+			"request_id_header": schema.StringAttribute{
+				Optional:    true,
+				Description: "Defaults to `X-Request-ID`. A unique correlation ID is sent in this header with every request. The ID, along with any request ID the API returns in the same header, is included in error messages so failures can be traced in the API's logs.",
+			},
 			"rate_limit": schema.Float64Attribute{
 				Optional:    true,
 				Description: "Set this to limit the number of requests per second made to the API. Must be a positive number.",
 			},
+			"adaptive_rate_limit": schema.BoolAttribute{
+				Optional:    true,
+				Description: "When set, the rate limit adapts to the `X-RateLimit-Limit`/`X-RateLimit-Remaining`/`X-RateLimit-Reset` and `RateLimit` headers returned by the API: requests are slowed down to spread the remaining quota over the time left until reset, and are paused until the reset when the quota is exhausted. The configured `rate_limit` remains the upper bound.",
+			},
 			"test_path": schema.StringAttribute{
 				Optional:    true,
 				Description: "If set, the provider will issue a read_method request to this path after instantiation requiring a 200 OK response before proceeding. This is useful if your API provides a no-op endpoint that can signal if this provider is configured correctly. Response data will be ignored.",
@@ -247,6 +370,95 @@ func (p *RestAPIProvider) Schema(ctx context.Context, req provider.SchemaRequest
 					},
 				},
 			},
+			"credential_process": schema.SingleNestedBlock{
+				Description: "Runs a local command to obtain the credentials sent with each request, similar to kubectl exec plugins or AWS `credential_process`. The command must print JSON of the form `{\"token\": \"...\", \"headers\": {\"X-Api-Key\": \"...\"}, \"expiration\": \"2006-01-02T15:04:05Z\"}` where all fields are optional but at least one of `token` or `headers` must be set. A token is sent as `Authorization: Bearer <token>` and headers are set over the provider's `headers`. The output is cached until shortly before the expiration (or for the rest of the run if there is none), and the command is run again if the API responds with 401 Unauthorized.",
+				Attributes: map[string]schema.Attribute{
+					"command": schema.StringAttribute{
+						Description: "The command to run. Either an absolute path or a command found in `PATH`.",
+						Optional:    true,
+					},
+					"args": schema.ListAttribute{
+						ElementType: types.StringType,
+						Optional:    true,
+						Description: "Arguments to pass to the command.",
+					},
+					"env": schema.MapAttribute{
+						ElementType: types.StringType,
+						Optional:    true,
+						Description: "Environment variables to set for the command, in addition to those of the provider process.",
+					},
+				},
+			},
+			"csrf": schema.SingleNestedBlock{
+				Description: "Sends a CSRF token with unsafe requests (any method other than GET, HEAD and OPTIONS), as required by many web-style admin APIs. The token is read from a cookie, from the JSON response of a token path, or from a cookie set by a request to the token path. If the API answers 403 Forbidden to a request that carried a token, or sets the token cookie along with the 403, the token is refreshed and the request sent once more. Other 403s are returned as is. Reading the token from a cookie enables the cookie jar.",
+				Attributes: map[string]schema.Attribute{
+					"cookie_name": schema.StringAttribute{
+						Description: "Name of the cookie holding the token, such as `csrftoken`.",
+						Optional:    true,
+					},
+					"token_path": schema.StringAttribute{
+						Description: "Path (relative to `uri`) to GET to obtain the token. The token is read from `token_key` in the response, or else from the `cookie_name` cookie the response sets. Fetched tokens are reused until the API rejects one.",
+						Optional:    true,
+					},
+					"token_key": schema.StringAttribute{
+						Description: "Key of the token in the JSON response of `token_path`. May be a '/'-delimited path if it is nested.",
+						Optional:    true,
+					},
+					"header_name": schema.StringAttribute{
+						Description: "Defaults to `X-CSRF-Token`. The header to send the token in.",
+						Optional:    true,
+					},
+				},
+			},
+			"rate_limit_budget": schema.ListNestedBlock{
+				Description: "A separate rate limit budget for requests matching an HTTP method and/or path prefix. Budgets are evaluated in order and the first match is used. Requests that do not match any budget use `rate_limit`.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"method": schema.StringAttribute{
+							Description: "HTTP method this budget applies to, such as `POST`. If omitted, the budget applies to all methods.",
+							Optional:    true,
+						},
+						"path_prefix": schema.StringAttribute{
+							Description: "Request path prefix (relative to `uri`) this budget applies to, such as `/api/search`. If omitted, the budget applies to all paths.",
+							Optional:    true,
+						},
+						"rate_limit": schema.Float64Attribute{
+							Description: "Maximum number of requests per second for this budget. Must be a positive number.",
+							Required:    true,
+						},
+					},
+				},
+			},
+			"max_concurrent_requests": schema.SingleNestedBlock{
+				Description: "Caps the number of API requests in flight at the same time across all resources and data sources using this provider, regardless of Terraform's `-parallelism`. Requests waiting for a slot are queued. Read requests are those using GET, HEAD or OPTIONS; all other methods are write requests.",
+				Attributes: map[string]schema.Attribute{
+					"total": schema.Int64Attribute{
+						Description: "Maximum number of requests of any kind in flight at once. Defaults to 0 (unlimited).",
+						Optional:    true,
+					},
+					"reads": schema.Int64Attribute{
+						Description: "Maximum number of read requests in flight at once. Defaults to 0 (unlimited).",
+						Optional:    true,
+					},
+					"writes": schema.Int64Attribute{
+						Description: "Maximum number of write requests in flight at once. Defaults to 0 (unlimited).",
+						Optional:    true,
+					},
+				},
+			},
+			"circuit_breaker": schema.SingleNestedBlock{
+				Description: "Stops sending requests to the API after a number of consecutive failures (connection errors or a 500-range response except 501, with each retry counting as an attempt) so that a plan against an unavailable API fails fast with a clear error instead of waiting on every resource. After the cooldown, a single request is sent to probe the API; if it succeeds, requests resume normally.",
+				Attributes: map[string]schema.Attribute{
+					"failure_threshold": schema.Int64Attribute{
+						Description: "Number of consecutive failed attempts, retries included, after which requests fail fast and are no longer retried. Defaults to 0 (disabled).",
+						Optional:    true,
+					},
+					"cooldown": schema.Int64Attribute{
+						Description: "Time in seconds to fail requests fast before probing the API again. Defaults to 30.",
+						Optional:    true,
+					},
+				},
+			},
 			"retries": schema.SingleNestedBlock{
 				Description: "Configuration for automatic retry (connection/TLS/etc errors or a 500-range response except 501) of failed HTTP requests",
 				Attributes: map[string]schema.Attribute{
@@ -298,12 +510,59 @@ func (p *RestAPIProvider) Configure(ctx context.Context, req provider.ConfigureR
 		}
 	}
 
+	// Extract path_variables from the map
+	var pathVariables map[string]string
+	if !data.PathVariables.IsNull() && !data.PathVariables.IsUnknown() {
+		diags := data.PathVariables.ElementsAs(ctx, &pathVariables, false)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Extract failover_status_codes from the list
+	var failoverStatusCodes []int
+	if !data.FailoverStatusCodes.IsNull() && !data.FailoverStatusCodes.IsUnknown() {
+		diags := data.FailoverStatusCodes.ElementsAs(ctx, &failoverStatusCodes, false)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Extract header_files from the map
+	var headerFiles map[string]string
+	if !data.HeaderFiles.IsNull() && !data.HeaderFiles.IsUnknown() {
+		diags := data.HeaderFiles.ElementsAs(ctx, &headerFiles, false)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	// Check for conflicting authentication methods
 	username := existingOrEnvOrDefaultString(&resp.Diagnostics, "username", data.Username, "REST_API_USERNAME", "", false)
 	password := existingOrEnvOrDefaultString(&resp.Diagnostics, "password", data.Password, "REST_API_PASSWORD", "", false)
+	passwordFile := existingOrEnvOrDefaultString(&resp.Diagnostics, "password_file", data.PasswordFile, "REST_API_PASSWORD_FILE", "", false)
 	bearerToken := existingOrEnvOrDefaultString(&resp.Diagnostics, "bearer_token", data.BearerToken, "REST_API_BEARER", "", false)
+	bearerTokenFile := existingOrEnvOrDefaultString(&resp.Diagnostics, "bearer_token_file", data.BearerTokenFile, "REST_API_BEARER_FILE", "", false)
+
+	if password != "" && passwordFile != "" {
+		resp.Diagnostics.AddError(
+			"Conflicting Password Configuration",
+			"Both password and password_file are set. Please use only one method to provide the password.",
+		)
+		return
+	}
+	if bearerToken != "" && bearerTokenFile != "" {
+		resp.Diagnostics.AddError(
+			"Conflicting Bearer Token Configuration",
+			"Both bearer_token and bearer_token_file are set. Please use only one method to provide the bearer token.",
+		)
+		return
+	}
 
-	if username != "" && password != "" && bearerToken != "" {
+	if username != "" && (password != "" || passwordFile != "") && (bearerToken != "" || bearerTokenFile != "") {
 		resp.Diagnostics.AddError(
 			"Conflicting Authentication Methods",
 			"Both basic auth (username/password) and bearer_token are set - please set only one authentication method",
@@ -318,19 +577,29 @@ func (p *RestAPIProvider) Configure(ctx context.Context, req provider.ConfigureR
 
 	// Populate default options
 	opt := &apiclient.APIClientOpt{
-		URI:                 existingOrEnvOrDefaultString(&resp.Diagnostics, "uri", data.URI, "REST_API_URI", "", true),
 		Insecure:            existingOrEnvOrDefaultBool(&resp.Diagnostics, "insecure", data.Insecure, "REST_API_INSECURE", false, false),
 		Username:            username,
 		Password:            password,
+		PasswordFile:        passwordFile,
+		BearerTokenFile:     bearerTokenFile,
 		Headers:             headers,
+		HeaderFiles:         headerFiles,
 		UseCookies:          existingOrEnvOrDefaultBool(&resp.Diagnostics, "use_cookies", data.UseCookies, "REST_API_USE_COOKIES", false, false),
+		CookieFile:          existingOrEnvOrDefaultString(&resp.Diagnostics, "cookie_file", data.CookieFile, "REST_API_COOKIE_FILE", "", false),
 		Timeout:             existingOrEnvOrDefaultInt(&resp.Diagnostics, "timeout", data.Timeout, "REST_API_TIMEOUT", 60, false),
 		IDAttribute:         existingOrEnvOrDefaultString(&resp.Diagnostics, "id_attribute", data.IDAttribute, "REST_API_ID_ATTRIBUTE", "id", false),
 		CopyKeys:            copyKeys,
+		PathVariables:       pathVariables,
+		FailoverStatusCodes: failoverStatusCodes,
 		WriteReturnsObject:  existingOrEnvOrDefaultBool(&resp.Diagnostics, "write_returns_object", data.WriteReturnsObject, "REST_API_WRO", false, false),
 		CreateReturnsObject: existingOrEnvOrDefaultBool(&resp.Diagnostics, "create_returns_object", data.CreateReturnsObject, "REST_API_CRO", false, false),
+		ResponseEnvelopeKey: existingOrEnvOrDefaultString(&resp.Diagnostics, "response_envelope_key", data.ResponseEnvelopeKey, "REST_API_RESPONSE_ENVELOPE_KEY", "", false),
+		RequestEnvelopeKey:  existingOrEnvOrDefaultString(&resp.Diagnostics, "request_envelope_key", data.RequestEnvelopeKey, "REST_API_REQUEST_ENVELOPE_KEY", "", false),
 		XSSIPrefix:          existingOrEnvOrDefaultString(&resp.Diagnostics, "xssi_prefix", data.XSSIPrefix, "REST_API_XSSI_PREFIX", "", false),
+		RequestIDHeader:     existingOrEnvOrDefaultString(&resp.Diagnostics, "request_id_header", data.RequestIDHeader, "REST_API_REQUEST_ID_HEADER", apiclient.DefaultRequestIDHeader, false),
+		UserAgent:           userAgent(p.version, req.TerraformVersion),
 		RateLimit:           existingOrEnvOrDefaultFloat(&resp.Diagnostics, "rate_limit", data.RateLimit, "REST_API_RATE_LIMIT", math.MaxFloat64, false),
+		AdaptiveRateLimit:   existingOrEnvOrDefaultBool(&resp.Diagnostics, "adaptive_rate_limit", data.AdaptiveRateLimit, "REST_API_ADAPTIVE_RATE_LIMIT", false, false),
 		Debug:               existingOrEnvOrDefaultBool(&resp.Diagnostics, "debug", data.Debug, "REST_API_DEBUG", false, false),
 		CreateMethod:        existingOrEnvOrDefaultString(&resp.Diagnostics, "create_method", data.CreateMethod, "REST_API_CREATE_METHOD", "POST", false),
 		ReadMethod:          existingOrEnvOrDefaultString(&resp.Diagnostics, "read_method", data.ReadMethod, "REST_API_READ_METHOD", "GET", false),
@@ -344,6 +613,32 @@ func (p *RestAPIProvider) Configure(ctx context.Context, req provider.ConfigureR
 		RootCAString:        existingOrEnvOrDefaultString(&resp.Diagnostics, "root_ca_string", data.RootCAString, "REST_API_ROOT_CA_STRING", "", false),
 	}
 
+	// A single base URI is set with uri, several to fail over between in order with uris
+	if !data.URI.IsNull() && !data.URIs.IsNull() {
+		resp.Diagnostics.AddError(
+			"Conflicting URI Configuration",
+			"Both uri and uris are set. Please use uri for a single base URI, or uris to fail over between several.",
+		)
+		return
+	}
+	uris := existingOrEnvOrDefaultStringList(ctx, &resp.Diagnostics, "uris", data.URIs, "REST_API_URIS", nil, false)
+	if !data.URIs.IsNull() && uris != nil && len(uris) == 0 {
+		resp.Diagnostics.AddError(
+			"Invalid URI Configuration",
+			"The uris configuration value must list at least one URI.",
+		)
+	}
+	if data.URIs.IsNull() && (!data.URI.IsNull() || len(uris) == 0) {
+		uris = nil
+		if uri := existingOrEnvOrDefaultString(&resp.Diagnostics, "uri", data.URI, "REST_API_URI", "", true); uri != "" {
+			uris = []string{uri}
+		}
+	}
+	if len(uris) > 0 {
+		opt.URI = uris[0]
+		opt.FailoverURIs = uris[1:]
+	}
+
 	// Handle retries configuration
 	if data.RetriesConfig != nil {
 		opt.RetryMax = existingOrEnvOrDefaultInt(&resp.Diagnostics, "retries.max_retries", data.RetriesConfig.MaxRetries, "REST_API_RETRY_MAX", 0, false)
@@ -351,11 +646,120 @@ func (p *RestAPIProvider) Configure(ctx context.Context, req provider.ConfigureR
 		opt.RetryWaitMax = existingOrEnvOrDefaultInt(&resp.Diagnostics, "retries.max_wait", data.RetriesConfig.MaxWait, "REST_API_RETRY_WAIT_MAX", 0, false)
 	}
 
-	if _, err := url.Parse(opt.URI); err != nil {
-		resp.Diagnostics.AddError(
-			"Invalid URI Configuration",
-			fmt.Sprintf("The uri configuration value must be a valid URI. The value '%s' is not valid: %s", opt.URI, err.Error()),
-		)
+	// Handle concurrency limits
+	if data.MaxConcurrent != nil {
+		opt.MaxConcurrentRequests = existingOrEnvOrDefaultInt(&resp.Diagnostics, "max_concurrent_requests.total", data.MaxConcurrent.Total, "REST_API_MAX_CONCURRENT_REQUESTS", 0, false)
+		opt.MaxConcurrentReads = existingOrEnvOrDefaultInt(&resp.Diagnostics, "max_concurrent_requests.reads", data.MaxConcurrent.Reads, "REST_API_MAX_CONCURRENT_READS", 0, false)
+		opt.MaxConcurrentWrites = existingOrEnvOrDefaultInt(&resp.Diagnostics, "max_concurrent_requests.writes", data.MaxConcurrent.Writes, "REST_API_MAX_CONCURRENT_WRITES", 0, false)
+	}
+
+	// Handle circuit breaker
+	if data.CircuitBreaker != nil {
+		opt.CircuitBreakerThreshold = existingOrEnvOrDefaultInt(&resp.Diagnostics, "circuit_breaker.failure_threshold", data.CircuitBreaker.FailureThreshold, "REST_API_CIRCUIT_BREAKER_THRESHOLD", 0, false)
+		opt.CircuitBreakerCooldown = existingOrEnvOrDefaultInt(&resp.Diagnostics, "circuit_breaker.cooldown", data.CircuitBreaker.Cooldown, "REST_API_CIRCUIT_BREAKER_COOLDOWN", 30, false)
+	}
+
+	// Handle named endpoints
+	for name, endpoint := range data.Endpoints {
+		e := apiclient.Endpoint{}
+		if !endpoint.Headers.IsNull() && !endpoint.Headers.IsUnknown() {
+			resp.Diagnostics.Append(endpoint.Headers.ElementsAs(ctx, &e.Headers, false)...)
+		}
+		var endpointURIs []string
+		switch {
+		case endpoint.URI.IsNull() == endpoint.URIs.IsNull():
+			resp.Diagnostics.AddError(
+				"Invalid Endpoint Configuration",
+				fmt.Sprintf("The endpoints[%q] value must set either uri or uris.", name),
+			)
+		case !endpoint.URIs.IsNull():
+			endpointURIs = knownStringList(ctx, &resp.Diagnostics, endpoint.URIs)
+			if endpointURIs != nil && len(endpointURIs) == 0 {
+				resp.Diagnostics.AddError(
+					"Invalid Endpoint Configuration",
+					fmt.Sprintf("The endpoints[%q].uris value must list at least one URI.", name),
+				)
+			}
+		case !endpoint.URI.IsUnknown():
+			endpointURIs = []string{endpoint.URI.ValueString()}
+		}
+		if len(endpointURIs) > 0 {
+			e.URI = endpointURIs[0]
+			e.FailoverURIs = endpointURIs[1:]
+		}
+		for _, u := range endpointURIs {
+			if _, err := url.Parse(u); err != nil || u == "" {
+				resp.Diagnostics.AddError(
+					"Invalid Endpoint Configuration",
+					fmt.Sprintf("The endpoints[%q] URIs must be valid URIs. The value '%s' is not valid.", name, u),
+				)
+			}
+		}
+		if opt.Endpoints == nil {
+			opt.Endpoints = map[string]apiclient.Endpoint{}
+		}
+		opt.Endpoints[name] = e
+	}
+
+	// Handle CSRF token
+	if data.CSRF != nil {
+		opt.CSRF = &apiclient.CSRFConfig{
+			CookieName: existingOrEnvOrDefaultString(&resp.Diagnostics, "csrf.cookie_name", data.CSRF.CookieName, "REST_API_CSRF_COOKIE_NAME", "", false),
+			TokenPath:  existingOrEnvOrDefaultString(&resp.Diagnostics, "csrf.token_path", data.CSRF.TokenPath, "REST_API_CSRF_TOKEN_PATH", "", false),
+			TokenKey:   existingOrEnvOrDefaultString(&resp.Diagnostics, "csrf.token_key", data.CSRF.TokenKey, "REST_API_CSRF_TOKEN_KEY", "", false),
+			HeaderName: existingOrEnvOrDefaultString(&resp.Diagnostics, "csrf.header_name", data.CSRF.HeaderName, "REST_API_CSRF_HEADER_NAME", apiclient.DefaultCSRFHeader, false),
+		}
+		if opt.CSRF.CookieName == "" && opt.CSRF.TokenPath == "" {
+			resp.Diagnostics.AddError(
+				"Invalid CSRF Configuration",
+				"The csrf block requires cookie_name, token_path, or both to know where to read the token from.",
+			)
+		}
+		if opt.CSRF.TokenPath != "" && opt.CSRF.TokenKey == "" && opt.CSRF.CookieName == "" {
+			resp.Diagnostics.AddError(
+				"Invalid CSRF Configuration",
+				"The csrf.token_path value requires token_key or cookie_name to know where in the response the token is.",
+			)
+		}
+	}
+
+	// Handle per method/path rate limit budgets
+	for i, budget := range data.RateLimitBudgets {
+		b := apiclient.RateLimitBudget{
+			Method:     existingOrDefaultString(budget.Method, ""),
+			PathPrefix: existingOrDefaultString(budget.PathPrefix, ""),
+			RateLimit:  budget.RateLimit.ValueFloat64(),
+		}
+		if b.RateLimit <= 0 {
+			resp.Diagnostics.AddError(
+				"Invalid Rate Limit Configuration",
+				fmt.Sprintf("The rate_limit_budget[%d].rate_limit value must be a positive number. The value %f is not valid.", i, b.RateLimit),
+			)
+		}
+		opt.RateLimitBudgets = append(opt.RateLimitBudgets, b)
+	}
+
+	for _, u := range uris {
+		if _, err := url.Parse(u); err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid URI Configuration",
+				fmt.Sprintf("The uri configuration value must be a valid URI. The value '%s' is not valid: %s", u, err.Error()),
+			)
+		} else if u == "" {
+			resp.Diagnostics.AddError(
+				"Invalid URI Configuration",
+				"The uri and uris configuration values must not be empty.",
+			)
+		}
+	}
+
+	for _, code := range opt.FailoverStatusCodes {
+		if code < 100 || code > 599 {
+			resp.Diagnostics.AddError(
+				"Invalid Failover Configuration",
+				fmt.Sprintf("The failover_status_codes values must be HTTP status codes. The value %d is not valid.", code),
+			)
+		}
 	}
 
 	if opt.Timeout < 0 {
@@ -372,6 +776,26 @@ func (p *RestAPIProvider) Configure(ctx context.Context, req provider.ConfigureR
 		)
 	}
 
+	if opt.MaxConcurrentRequests < 0 || opt.MaxConcurrentReads < 0 || opt.MaxConcurrentWrites < 0 {
+		resp.Diagnostics.AddError(
+			"Invalid Concurrency Configuration",
+			fmt.Sprintf("The max_concurrent_requests values must be non-negative. The values total=%d, reads=%d, writes=%d are not valid.", opt.MaxConcurrentRequests, opt.MaxConcurrentReads, opt.MaxConcurrentWrites),
+		)
+	}
+
+	if opt.CircuitBreakerThreshold < 0 {
+		resp.Diagnostics.AddError(
+			"Invalid Circuit Breaker Configuration",
+			fmt.Sprintf("The circuit_breaker.failure_threshold value must be non-negative. The value %d is not valid.", opt.CircuitBreakerThreshold),
+		)
+	}
+	if opt.CircuitBreakerCooldown < 0 {
+		resp.Diagnostics.AddError(
+			"Invalid Circuit Breaker Configuration",
+			fmt.Sprintf("The circuit_breaker.cooldown value must be non-negative. The value %d is not valid.", opt.CircuitBreakerCooldown),
+		)
+	}
+
 	if opt.RetryMax < 0 {
 		resp.Diagnostics.AddError(
 			"Invalid Retry Configuration",
@@ -477,6 +901,20 @@ func (p *RestAPIProvider) Configure(ctx context.Context, req provider.ConfigureR
 		}
 	}
 
+	// Handle credential process if provided
+	if data.CredentialProcess != nil {
+		process := &apiclient.CredentialProcess{
+			Command: existingOrEnvOrDefaultString(&resp.Diagnostics, "credential_process.command", data.CredentialProcess.Command, "REST_API_CREDENTIAL_PROCESS_COMMAND", "", true),
+		}
+		if !data.CredentialProcess.Args.IsNull() && !data.CredentialProcess.Args.IsUnknown() {
+			resp.Diagnostics.Append(data.CredentialProcess.Args.ElementsAs(ctx, &process.Args, false)...)
+		}
+		if !data.CredentialProcess.Env.IsNull() && !data.CredentialProcess.Env.IsUnknown() {
+			resp.Diagnostics.Append(data.CredentialProcess.Env.ElementsAs(ctx, &process.Env, false)...)
+		}
+		opt.CredentialProcess = process
+	}
+
 	// Final check for config errors
 	if resp.Diagnostics.HasError() {
 		return
@@ -546,6 +984,14 @@ func (p *RestAPIProvider) Functions(ctx context.Context) []func() function.Funct
 	return []func() function.Function{}
 }
 
+// userAgent builds the User-Agent sent with API requests, following the format Terraform's own providers use
+func userAgent(version string, terraformVersion string) string {
+	if terraformVersion == "" {
+		terraformVersion = "unknown"
+	}
+	return fmt.Sprintf("Terraform/%s (+https://www.terraform.io) terraform-provider-restapi/%s", terraformVersion, version)
+}
+
 func New(version string) func() provider.Provider {
 	return func() provider.Provider {
 		return &RestAPIProvider{
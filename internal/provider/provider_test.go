@@ -74,6 +74,157 @@ func TestProvider_valid(t *testing.T) {
 				})
 			}`,
 
+		"with_rate_limit_budgets": `
+			provider "restapi" {
+               	uri = "http://localhost:8080/"
+				rate_limit          = 10
+				adaptive_rate_limit = true
+
+				rate_limit_budget {
+					method     = "POST"
+					rate_limit = 1
+				}
+				rate_limit_budget {
+					path_prefix = "/api/search"
+					rate_limit  = 2
+				}
+			}
+			resource "restapi_object" "test" {
+				path = "/api/objects"
+				data = jsonencode({
+					id = "55555"
+					first = "Foo"
+					last = "Bar"
+				})
+			}`,
+
+		"with_max_concurrent_requests": `
+			provider "restapi" {
+               	uri = "http://localhost:8080/"
+
+				max_concurrent_requests {
+					total  = 10
+					reads  = 8
+					writes = 3
+				}
+			}
+			resource "restapi_object" "test" {
+				path = "/api/objects"
+				data = jsonencode({
+					id = "55555"
+					first = "Foo"
+					last = "Bar"
+				})
+			}`,
+
+		"with_endpoints": `
+			provider "restapi" {
+               	uri = "http://localhost:8080/"
+				endpoints = {
+					eu = {
+						uri = "http://localhost:8081/"
+						headers = {
+							"X-Region" = "eu"
+						}
+					}
+					v2 = {
+						uri = "http://localhost:8080/v2"
+					}
+					ha = {
+						uris = ["http://localhost:8080/v3", "http://localhost:8081/v3"]
+					}
+				}
+			}
+			resource "restapi_object" "test" {
+				path = "/api/objects"
+				endpoint = "eu"
+				data = jsonencode({
+					id = "55555"
+					first = "Foo"
+					last = "Bar"
+				})
+			}`,
+		"with_failover": `
+			provider "restapi" {
+               	uris = ["http://localhost:8080/", "http://localhost:8081/"]
+				failover_status_codes = [502, 503]
+			}
+			resource "restapi_object" "test" {
+				path = "/api/objects"
+				data = jsonencode({
+					id = "55555"
+					first = "Foo"
+					last = "Bar"
+				})
+			}`,
+		"with_credential_process": `
+			provider "restapi" {
+               	uri = "http://localhost:8080/"
+				credential_process {
+					command = "get-token"
+					args    = ["--audience", "api"]
+					env = {
+						TOKEN_PROFILE = "ci"
+					}
+				}
+			}
+			resource "restapi_object" "test" {
+				path = "/api/objects"
+				data = jsonencode({
+					id = "55555"
+					first = "Foo"
+					last = "Bar"
+				})
+			}`,
+		"with_csrf": `
+			provider "restapi" {
+               	uri = "http://localhost:8080/"
+				cookie_file = "cookies.json"
+				csrf {
+					token_path = "/api/csrf"
+					token_key  = "token"
+				}
+			}
+			resource "restapi_object" "test" {
+				path = "/api/objects"
+				data = jsonencode({
+					id = "55555"
+					first = "Foo"
+					last = "Bar"
+				})
+			}`,
+		"with_request_id_header": `
+			provider "restapi" {
+               	uri = "http://localhost:8080/"
+				request_id_header = "X-Correlation-ID"
+			}
+			resource "restapi_object" "test" {
+				path = "/api/objects"
+				data = jsonencode({
+					id = "55555"
+					first = "Foo"
+					last = "Bar"
+				})
+			}`,
+
+		"with_circuit_breaker": `
+			provider "restapi" {
+               	uri = "http://localhost:8080/"
+
+				circuit_breaker {
+					failure_threshold = 5
+					cooldown          = 60
+				}
+			}
+			resource "restapi_object" "test" {
+				path = "/api/objects"
+				data = jsonencode({
+					id = "55555"
+					first = "Foo"
+					last = "Bar"
+				})
+			}`,
+
 		"oauth_with_endpoint_params": `
 			provider "restapi" {
                	uri = "http://localhost:8080/"
@@ -135,6 +286,25 @@ func TestProvider_invalid(t *testing.T) {
 			}
 		`,
 
+		"uri_and_uris": `
+			provider "restapi" {
+				uri  = "http://localhost:8080/"
+				uris = ["http://localhost:8080/", "http://localhost:8081/"]
+			}
+			data "restapi_object" "test" {
+				path = "/api/test"
+			}
+		`,
+
+		"empty_uris": `
+			provider "restapi" {
+				uris = []
+			}
+			data "restapi_object" "test" {
+				path = "/api/test"
+			}
+		`,
+
 		"bad_types": `
 			provider "restapi" {
 				uri = "http://localhost:8080/"
@@ -192,6 +362,142 @@ func TestProvider_invalid(t *testing.T) {
 			}
 		`,
 
+		"rate_limit_budget_negative": `
+			provider "restapi" {
+				uri = "http://localhost:8080/"
+				rate_limit_budget {
+					method     = "POST"
+					rate_limit = -1
+				}
+			}
+			data "restapi_object" "test" {
+				path = "/api/test"
+			}
+		`,
+
+		"max_concurrent_requests_negative": `
+			provider "restapi" {
+				uri = "http://localhost:8080/"
+				max_concurrent_requests {
+					writes = -3
+				}
+			}
+			data "restapi_object" "test" {
+				path = "/api/test"
+			}
+		`,
+
+		"failover_invalid_status_code": `
+			provider "restapi" {
+				uris = ["http://localhost:8080/", "http://localhost:8081/"]
+				failover_status_codes = [42]
+			}
+			data "restapi_object" "test" {
+				path = "/api/test"
+			}
+		`,
+
+		"credential_process_missing_command": `
+			provider "restapi" {
+				uri = "http://localhost:8080/"
+				credential_process {
+					args = ["--audience", "api"]
+				}
+			}
+			data "restapi_object" "test" {
+				path = "/api/test"
+			}
+		`,
+
+		"bearer_token_and_file": `
+			provider "restapi" {
+				uri = "http://localhost:8080/"
+				bearer_token = "token"
+				bearer_token_file = "/var/run/secrets/token"
+			}
+			data "restapi_object" "test" {
+				path = "/api/test"
+			}
+		`,
+
+		"password_and_file": `
+			provider "restapi" {
+				uri = "http://localhost:8080/"
+				username = "user"
+				password = "password"
+				password_file = "/var/run/secrets/password"
+			}
+			data "restapi_object" "test" {
+				path = "/api/test"
+			}
+		`,
+
+		"csrf_without_source": `
+			provider "restapi" {
+				uri = "http://localhost:8080/"
+				csrf {
+					header_name = "X-XSRF-Token"
+				}
+			}
+			data "restapi_object" "test" {
+				path = "/api/test"
+			}
+		`,
+
+		"csrf_token_path_without_key": `
+			provider "restapi" {
+				uri = "http://localhost:8080/"
+				csrf {
+					token_path = "/api/csrf"
+				}
+			}
+			data "restapi_object" "test" {
+				path = "/api/test"
+			}
+		`,
+
+		"endpoint_invalid_uri": `
+			provider "restapi" {
+				uri = "http://localhost:8080/"
+				endpoints = {
+					bad = {
+						uri = "::::invalid-uri"
+					}
+				}
+			}
+			data "restapi_object" "test" {
+				path = "/api/test"
+			}
+		`,
+
+		"endpoint_without_uri": `
+			provider "restapi" {
+				uri = "http://localhost:8080/"
+				endpoints = {
+					bad = {
+						headers = {
+							"X-Region" = "eu"
+						}
+					}
+				}
+			}
+			data "restapi_object" "test" {
+				path = "/api/test"
+			}
+		`,
+
+		"circuit_breaker_negative_threshold": `
+			provider "restapi" {
+				uri = "http://localhost:8080/"
+				circuit_breaker {
+					failure_threshold = -1
+				}
+			}
+			data "restapi_object" "test" {
+				path = "/api/test"
+			}
+		`,
+
 		"rate_limit_negative": `
 			provider "restapi" {
 				uri = "http://localhost:8080/"
@@ -480,3 +786,12 @@ func TestProvider_KeyStringIsSensitive(t *testing.T) {
 		t.Error("key_string must be marked Sensitive: true to protect inline mTLS private key material")
 	}
 }
+
+func TestUserAgent(t *testing.T) {
+	if got, want := userAgent("1.2.3", "1.9.5"), "Terraform/1.9.5 (+https://www.terraform.io) terraform-provider-restapi/1.2.3"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if got, want := userAgent("dev", ""), "Terraform/unknown (+https://www.terraform.io) terraform-provider-restapi/dev"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
@@ -5,11 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/Mastercard/terraform-provider-restapi/internal/apiclient"
 	restapi "github.com/Mastercard/terraform-provider-restapi/internal/apiclient"
 	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -30,6 +34,7 @@ type RestAPIObjectResource struct {
 
 type RestAPIObjectResourceModel struct {
 	Path                   types.String         `tfsdk:"path"`
+	Endpoint               types.String         `tfsdk:"endpoint"`
 	CreatePath             types.String         `tfsdk:"create_path"`
 	ReadPath               types.String         `tfsdk:"read_path"`
 	UpdatePath             types.String         `tfsdk:"update_path"`
@@ -39,15 +44,26 @@ type RestAPIObjectResourceModel struct {
 	UpdateMethod           types.String         `tfsdk:"update_method"`
 	DestroyMethod          types.String         `tfsdk:"destroy_method"`
 	IDAttribute            types.String         `tfsdk:"id_attribute"`
+	IDAttributes           types.List           `tfsdk:"id_attributes"`
+	IDSeparator            types.String         `tfsdk:"id_separator"`
 	ObjectID               types.String         `tfsdk:"object_id"`
 	Data                   jsontypes.Normalized `tfsdk:"data"`
 	Debug                  types.Bool           `tfsdk:"debug"`
 	ReadSearch             *ReadSearchModel     `tfsdk:"read_search"`
+	WaitForDeletion        *WaitForDeleteModel  `tfsdk:"wait_for_deletion"`
 	QueryString            types.String         `tfsdk:"query_string"`
 	ForceNew               types.List           `tfsdk:"force_new"`
 	ReadData               jsontypes.Normalized `tfsdk:"read_data"`
 	UpdateData             jsontypes.Normalized `tfsdk:"update_data"`
+	UpdateStrategy         types.String         `tfsdk:"update_strategy"`
+	OnConflict             types.String         `tfsdk:"on_conflict"`
 	DestroyData            jsontypes.Normalized `tfsdk:"destroy_data"`
+	DestroyPolicy          types.String         `tfsdk:"destroy_policy"`
+	ResetData              jsontypes.Normalized `tfsdk:"reset_data"`
+	ReadPatch              jsontypes.Normalized `tfsdk:"read_patch"`
+	WritePatch             jsontypes.Normalized `tfsdk:"write_patch"`
+	ResponseEnvelopeKey    types.String         `tfsdk:"response_envelope_key"`
+	RequestEnvelopeKey     types.String         `tfsdk:"request_envelope_key"`
 	IgnoreChangesTo        types.List           `tfsdk:"ignore_changes_to"`
 	IgnoreAllServerChanges types.Bool           `tfsdk:"ignore_all_server_changes"`
 	IgnoreServerAdditions  types.Bool           `tfsdk:"ignore_server_additions"`
@@ -56,15 +72,43 @@ type RestAPIObjectResourceModel struct {
 	APIData        types.Map    `tfsdk:"api_data"`
 	APIResponse    types.String `tfsdk:"api_response"`
 	CreateResponse types.String `tfsdk:"create_response"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// privateConfiguredData is the private state key of the data as configured at the last apply,
+// as the data in state is replaced with what the API returns when the object is read
+const privateConfiguredData = "configured_data"
+
+// defaultOperationTimeout is the deadline for a create, read, update or delete
+// (including retries and rate limit waits) when no timeouts block is configured.
+// Zero means the operation has no deadline.
+const defaultOperationTimeout time.Duration = 0
+
+// withOperationTimeout returns a context with the deadline of an operation, if it has one
+func withOperationTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+type WaitForDeleteModel struct {
+	Interval   types.String `tfsdk:"interval"`
+	Timeout    types.String `tfsdk:"timeout"`
+	StateKey   types.String `tfsdk:"state_key"`
+	StateValue types.String `tfsdk:"state_value"`
 }
 
 type ReadSearchModel struct {
-	SearchData  jsontypes.Normalized `tfsdk:"search_data"`
-	SearchKey   types.String         `tfsdk:"search_key"`
-	SearchValue types.String         `tfsdk:"search_value"`
-	ResultsKey  types.String         `tfsdk:"results_key"`
-	QueryString types.String         `tfsdk:"query_string"`
-	SearchPatch jsontypes.Normalized `tfsdk:"search_patch"`
+	SearchData   jsontypes.Normalized `tfsdk:"search_data"`
+	SearchKey    types.String         `tfsdk:"search_key"`
+	SearchValue  types.String         `tfsdk:"search_value"`
+	SearchFilter types.String         `tfsdk:"search_filter"`
+	MatchPolicy  types.String         `tfsdk:"match_policy"`
+	ResultsKey   types.String         `tfsdk:"results_key"`
+	QueryString  types.String         `tfsdk:"query_string"`
+	SearchPatch  jsontypes.Normalized `tfsdk:"search_patch"`
 }
 
 func NewRestAPIObjectResource() resource.Resource {
@@ -84,19 +128,23 @@ func (r *RestAPIObjectResource) Schema(ctx context.Context, req resource.SchemaR
 		MarkdownDescription: "Acting as a restful API client, this object supports POST, GET, PUT and DELETE on the specified url",
 		Attributes: map[string]schema.Attribute{
 			"path": schema.StringAttribute{
-				Description: "The API path on top of the base URL set in the provider that represents objects of this type on the API server.",
+				Description: "The API path on top of the base URL set in the provider that represents objects of this type on the API server. Placeholders such as `{org_id}` are replaced with the value at that key in `data`, then `api_data`, then the provider's `path_variables` (nested keys are written `{parent/child}`). Values are percent-encoded; write `{+name}` to insert a value as-is.",
 				Required:    true,
 			},
+			"endpoint": schema.StringAttribute{
+				Description: "The name of one of the provider's `endpoints` to send this object's requests to. If not set, the provider's `uri` is used. Changing it replaces the object.",
+				Optional:    true,
+			},
 			"create_path": schema.StringAttribute{
-				Description: "Defaults to `path`. The API path that represents where to CREATE (POST) objects of this type on the API server. The string `{id}` will be replaced with the terraform ID of the object if the data contains the `id_attribute`.",
+				Description: "Defaults to `path`. The API path that represents where to CREATE (POST) objects of this type on the API server. The string `{id}` will be replaced with the terraform ID of the object if the data contains the `id_attribute`, and other placeholders are replaced as described for `path`.",
 				Optional:    true,
 			},
 			"read_path": schema.StringAttribute{
-				Description: "Defaults to `path/{id}`. The API path that represents where to READ (GET) objects of this type on the API server. The string `{id}` will be replaced with the terraform ID of the object.",
+				Description: "Defaults to `path/{id}`. The API path that represents where to READ (GET) objects of this type on the API server. The string `{id}` will be replaced with the terraform ID of the object, and other placeholders are replaced as described for `path`.",
 				Optional:    true,
 			},
 			"update_path": schema.StringAttribute{
-				Description: "Defaults to `path/{id}`. The API path that represents where to UPDATE (PUT) objects of this type on the API server. The string `{id}` will be replaced with the terraform ID of the object.",
+				Description: "Defaults to `path/{id}`. The API path that represents where to UPDATE (PUT) objects of this type on the API server. The string `{id}` will be replaced with the terraform ID of the object, and other placeholders are replaced as described for `path`.",
 				Optional:    true,
 			},
 			"create_method": schema.StringAttribute{
@@ -116,19 +164,32 @@ func (r *RestAPIObjectResource) Schema(ctx context.Context, req resource.SchemaR
 				Optional:    true,
 			},
 			"destroy_path": schema.StringAttribute{
-				Description: "Defaults to `path/{id}`. The API path that represents where to DESTROY (DELETE) objects of this type on the API server. The string `{id}` will be replaced with the terraform ID of the object.",
+				Description: "Defaults to `path/{id}`. The API path that represents where to DESTROY (DELETE) objects of this type on the API server. The string `{id}` will be replaced with the terraform ID of the object, and other placeholders are replaced as described for `path`.",
+				Optional:    true,
+			},
+			"on_conflict": schema.StringAttribute{
 				Optional:    true,
+				Description: "Defaults to `error`. What to do when creating an object that already exists: `error` just creates it, failing if the API returns 409 Conflict; `adopt` first looks the object up with `read_search` (and again if the create returns 409 Conflict) and, if it is found, takes over its id instead of creating it, so differences with `data` show up in the next plan; `adopt_and_update` also updates the adopted object with `data` right away. `adopt` and `adopt_and_update` require `read_search`.",
 			},
 			"id_attribute": schema.StringAttribute{
 				Description: "Defaults to `id_attribute` set on the provider. Allows per-resource override of `id_attribute` (see `id_attribute` provider config documentation)",
 				Optional:    true,
 			},
+			"id_attributes": schema.ListAttribute{
+				ElementType: types.StringType,
+				Description: "For APIs that identify objects by several fields, such as a zone and a name, the paths of those fields (in the same format as `id_attribute`). The id is their values joined by `id_separator`, and is what `{id}` is replaced with in paths; each part is also available as a placeholder of its own, such as `{zone}`. Conflicts with `id_attribute`.",
+				Optional:    true,
+			},
+			"id_separator": schema.StringAttribute{
+				Description: "Defaults to `:`. The separator between the parts of an id made from `id_attributes`.",
+				Optional:    true,
+			},
 			"object_id": schema.StringAttribute{
 				Description: "Defaults to the id learned by the provider during normal operations and `id_attribute`. Allows you to set the id manually. This is used in conjunction with the `*_path` attributes.",
 				Optional:    true,
 			},
 			"data": schema.StringAttribute{
-				Description: "Valid JSON object that this provider will manage with the API server.",
+				Description: "Valid JSON that this provider will manage with the API server. This is usually an object, but can be an array or a value, such as `[\"10.0.0.0/8\"]` for an allowlist. As there is no `id_attribute` to read the id from then, `object_id` must be set.",
 				Required:    true,
 				Sensitive:   isDataSensitive,
 				CustomType:  jsontypes.NormalizedType{},
@@ -145,7 +206,7 @@ func (r *RestAPIObjectResource) Schema(ctx context.Context, req resource.SchemaR
 			"force_new": schema.ListAttribute{
 				ElementType: types.StringType,
 				Optional:    true,
-				Description: "Any changes to these values will result in recreating the resource instead of updating.",
+				Description: "Any changes to these values will result in recreating the resource instead of updating. Nested fields use the dot syntax ('metadata.name') or a JSON Pointer ('/metadata/name', '/rules/0/name').",
 			},
 			"read_data": schema.StringAttribute{
 				Optional:    true,
@@ -159,16 +220,48 @@ func (r *RestAPIObjectResource) Schema(ctx context.Context, req resource.SchemaR
 				Sensitive:   isDataSensitive,
 				CustomType:  jsontypes.NormalizedType{},
 			},
+			"update_strategy": schema.StringAttribute{
+				Optional:    true,
+				Description: "Defaults to `full`. What to send to update the object, computed from the prior state and the planned `data`: `full` sends the whole `data`; `merge_patch` sends an RFC 7396 JSON Merge Patch (`application/merge-patch+json`); `json_patch` sends an RFC 6902 JSON Patch (`application/json-patch+json`); `changed_fields` sends only the top-level fields that changed, with removed fields set to null. Strategies other than `full` default `update_method` to `PATCH` and skip the request when `data` is unchanged. Only the fields configured in `data`, now or at the last apply, are compared, so fields the API adds of its own are left alone and fields removed from `data` are removed. Ignored if `update_data` is set.",
+			},
 			"destroy_data": schema.StringAttribute{
 				Optional:    true,
 				Description: "Valid JSON object to pass during to destroy requests.",
 				Sensitive:   isDataSensitive,
 				CustomType:  jsontypes.NormalizedType{},
 			},
+			"destroy_policy": schema.StringAttribute{
+				Optional:    true,
+				Description: "Defaults to `delete`. What to do when the object is destroyed: `delete` sends the destroy request; `abandon` only removes the object from the Terraform state and leaves it on the API; `protect` fails any plan that would destroy or replace the object (set another policy and apply it first to destroy it); `reset` sends `reset_data` to `update_path` with `update_method` (ignoring the `PATCH` default of `update_strategy`) instead, for singleton objects such as settings that cannot be deleted.",
+			},
+			"reset_data": schema.StringAttribute{
+				Optional:    true,
+				Description: "Valid JSON object to send to reset the object when it is destroyed with `destroy_policy` set to `reset`.",
+				Sensitive:   isDataSensitive,
+				CustomType:  jsontypes.NormalizedType{},
+			},
+			"read_patch": schema.StringAttribute{
+				Optional:    true,
+				Description: "A JSON Patch (RFC 6902) applied to the object whenever it is read from the API, including create and update responses, `read_search` results (after `search_patch`) and imports, before it is compared with `data`. Use it to reshape the object to match `data`, for example to unwrap it or to rename, move or remove server-only fields. Removing a field the object doesn't have is not an error. `id_attribute` refers to the patched object.",
+				CustomType:  jsontypes.NormalizedType{},
+			},
+			"write_patch": schema.StringAttribute{
+				Optional:    true,
+				Description: "A JSON Patch (RFC 6902) applied to `data` before it is sent to create or update the object, usually the reverse of `read_patch`. Update strategies other than `full` compare the patched data. Not applied to `update_data`, `destroy_data` or `reset_data`.",
+				CustomType:  jsontypes.NormalizedType{},
+			},
+			"response_envelope_key": schema.StringAttribute{
+				Optional:    true,
+				Description: "Overrides the provider's `response_envelope_key` for this object. The path of the object in responses to create, read and update requests, such as `data` for `{\"data\": {...}, \"meta\": {...}}`. Like `results_key`, it is a path of keys separated by slashes or a JSON Pointer. The object is unwrapped before `read_patch` is applied, so `api_data`, `api_response` and drift detection only see the object.",
+			},
+			"request_envelope_key": schema.StringAttribute{
+				Optional:    true,
+				Description: "Overrides the provider's `request_envelope_key` for this object. The path `data` is nested under when it is sent to create or update the object, such as `item` to send `{\"item\": {...}}`, after `write_patch` is applied. Update patches are wrapped the same way. Not applied to `update_data`, `destroy_data` or `reset_data`.",
+			},
 			"ignore_changes_to": schema.ListAttribute{
 				ElementType: types.StringType,
 				Optional:    true,
-				Description: "A list of fields to which remote changes will be ignored. For example, an API might add or remove metadata, such as a 'last_modified' field, which Terraform should not attempt to correct. To ignore changes to nested fields, use the dot syntax: 'metadata.timestamp'",
+				Description: "A list of fields to which remote changes will be ignored. For example, an API might add or remove metadata, such as a 'last_modified' field, which Terraform should not attempt to correct. To ignore changes to nested fields, use the dot syntax: 'metadata.timestamp', or a JSON Pointer (RFC 6901) such as '/metadata/timestamp', which can also address keys containing dots or slashes ('/labels/app.kubernetes.io~1name') and list elements ('/rules/0/hits').",
 				Sensitive:   isDataSensitive,
 				// TODO ValidateFunc not supported for lists, but should probably validate that the ignore paths are valid
 			},
@@ -181,7 +274,7 @@ func (r *RestAPIObjectResource) Schema(ctx context.Context, req resource.SchemaR
 				Optional:    true,
 			},
 			"read_search": schema.SingleNestedAttribute{
-				Description: "Custom search for `read_path`. This map will take `search_data`, `search_key`, `search_value`, `results_key` and `query_string` (see datasource config documentation)",
+				Description: "Custom search for `read_path`. This map will take `search_data`, `search_key`, `search_value`, `search_filter`, `match_policy`, `results_key` and `query_string` (see datasource config documentation)",
 				Optional:    true,
 				Attributes: map[string]schema.Attribute{
 					"query_string": schema.StringAttribute{
@@ -194,15 +287,23 @@ func (r *RestAPIObjectResource) Schema(ctx context.Context, req resource.SchemaR
 						CustomType:  jsontypes.NormalizedType{},
 					},
 					"search_key": schema.StringAttribute{
-						Description: "When reading search results from the API, this key is used to identify the specific record to read. This should be a unique record such as 'name'. Similar to results_key, the value may be in the format of 'field/field/field' to search for data deeper in the returned object.",
-						Required:    true,
+						Description: "When reading search results from the API, this key is used to identify the specific record to read. This should be a unique record such as 'name'. Similar to results_key, the value may be in the format of 'field/field/field' to search for data deeper in the returned object, or a JSON Pointer such as '/meta/name'. Required unless `search_filter` is set.",
+						Optional:    true,
 					},
 					"search_value": schema.StringAttribute{
-						Description: "The value of 'search_key' will be compared to this value to determine if the correct object was found. Example: if 'search_key' is 'name' and 'search_value' is 'foo', the record in the array returned by the API with name=foo will be used. Supports interpolation of {id} placeholder with the object's ID.",
-						Required:    true,
+						Description: "The value of 'search_key' will be compared to this value to determine if the correct object was found. Example: if 'search_key' is 'name' and 'search_value' is 'foo', the record in the array returned by the API with name=foo will be used. Supports interpolation of {id} placeholder with the object's ID. Required unless `search_filter` is set.",
+						Optional:    true,
+					},
+					"search_filter": schema.StringAttribute{
+						Description: "A JSONPath filter expression the record to read must match, as an alternative to `search_key` and `search_value`. Example: `@.name == 'foo' && @.env =~ /(?i)^prod/`. Supports `==`, `!=`, `<`, `<=`, `>`, `>=`, `=~` (regular expressions, `(?i)` for case-insensitive), `&&`, `||` and nested fields (`@.meta.name`). A full JSONPath starting with `$`, applied to the results array, can be given instead. Supports interpolation of {id} placeholder with the object's ID, escaped for use in a quoted string such as `@.id == '{id}'`.",
+						Optional:    true,
+					},
+					"match_policy": schema.StringAttribute{
+						Description: "Defaults to `first`. Which record to read when several match: `first` or `last` uses the first or last one in the results, and `unique` fails with the ids of the matching records, so that a name that isn't unique doesn't make Terraform manage the wrong object.",
+						Optional:    true,
 					},
 					"results_key": schema.StringAttribute{
-						Description: "When issuing a GET to the path, this JSON key is used to locate the results array. The format is 'field/field/field'. Example: 'results/values'. If omitted, it is assumed the results coming back are already an array and are to be used exactly as-is.",
+						Description: "When issuing a GET to the path, this JSON key is used to locate the results array. The format is 'field/field/field'. Example: 'results/values'. A JSON Pointer such as '/results/values' can also be used. If omitted, it is assumed the results coming back are already an array and are to be used exactly as-is.",
 						Optional:    true,
 					},
 					"search_patch": schema.StringAttribute{
@@ -212,6 +313,28 @@ func (r *RestAPIObjectResource) Schema(ctx context.Context, req resource.SchemaR
 					},
 				},
 			},
+			"wait_for_deletion": schema.SingleNestedAttribute{
+				Description: "For APIs that delete objects asynchronously, waits after the delete request until the object is gone before the destroy completes: until reading it returns 404 or 410, or `read_search` no longer finds it, or its `state_key` has the value `state_value`. This avoids conflicts when an object is replaced by one with the same name.",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"interval": schema.StringAttribute{
+						Description: "Defaults to `5s`. How often to check whether the object is gone. A duration such as `10s` or `1m`.",
+						Optional:    true,
+					},
+					"timeout": schema.StringAttribute{
+						Description: "How long to wait at most before failing the destroy. A duration such as `30s` or `2h45m`. Defaults to the rest of the `delete` timeout.",
+						Optional:    true,
+					},
+					"state_key": schema.StringAttribute{
+						Description: "The key of a field that reports the object's state, in the format of `id_attribute`. When set, the object also counts as gone once this field has the value `state_value`.",
+						Optional:    true,
+					},
+					"state_value": schema.StringAttribute{
+						Description: "The value of `state_key` once the object is deleted, such as `DELETED`.",
+						Optional:    true,
+					},
+				},
+			},
 
 			"create_response": schema.StringAttribute{
 				Description: "The raw body of the HTTP response returned when creating the object.",
@@ -234,6 +357,18 @@ func (r *RestAPIObjectResource) Schema(ctx context.Context, req resource.SchemaR
 				Computed:    true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create:            true,
+				Read:              true,
+				Update:            true,
+				Delete:            true,
+				CreateDescription: "Deadline for creating the object, including retries and rate limit waits. A duration such as `30s` or `2h45m`. Defaults to no deadline.",
+				ReadDescription:   "Deadline for reading the object during a refresh, including retries and rate limit waits. A duration such as `30s` or `2h45m`. Defaults to no deadline.",
+				UpdateDescription: "Deadline for updating the object, including retries and rate limit waits. A duration such as `30s` or `2h45m`. Defaults to no deadline.",
+				DeleteDescription: "Deadline for deleting the object, including retries and rate limit waits. A duration such as `30s` or `2h45m`. Defaults to no deadline. Only takes effect if the value is saved into state before the destroy.",
+			}),
+		},
 	}
 }
 
@@ -266,6 +401,14 @@ func (r *RestAPIObjectResource) Create(ctx context.Context, req resource.CreateR
 
 	tflog.Debug(ctx, "Create routine called", map[string]interface{}{"object": plan})
 
+	timeout, diags := plan.Timeouts.Create(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withOperationTimeout(ctx, timeout)
+	defer cancel()
+
 	client, err := r.providerData.GetClient()
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -287,12 +430,15 @@ func (r *RestAPIObjectResource) Create(ctx context.Context, req resource.CreateR
 	err = obj.CreateObject(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Error Creating API Object",
+			apiErrorSummary("Error Creating API Object", err),
 			fmt.Sprintf("Could not create API object: %s", err.Error()),
 		)
 		return
 	}
 
+	// Keep the data as configured, for update strategies to know which fields are managed
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, privateConfiguredData, []byte(plan.Data.ValueString()))...)
+
 	setResourceModelData(ctx, obj, &plan, &resp.Diagnostics)
 	plan.CreateResponse = types.StringValue(obj.GetApiResponse())
 
@@ -310,6 +456,14 @@ func (r *RestAPIObjectResource) Read(ctx context.Context, req resource.ReadReque
 
 	tflog.Debug(ctx, "Read routine called", map[string]interface{}{"object": state})
 
+	timeout, diags := state.Timeouts.Read(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withOperationTimeout(ctx, timeout)
+	defer cancel()
+
 	client, err := r.providerData.GetClient()
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -337,7 +491,7 @@ func (r *RestAPIObjectResource) Read(ctx context.Context, req resource.ReadReque
 	if err != nil {
 		tflog.Error(ctx, "Error reading API object", map[string]interface{}{"error": err})
 		resp.Diagnostics.AddError(
-			"Error Reading API Object",
+			apiErrorSummary("Error Reading API Object", err),
 			fmt.Sprintf("Could not read API object: %s", err.Error()),
 		)
 		return
@@ -412,8 +566,13 @@ func (r *RestAPIObjectResource) Read(ctx context.Context, req resource.ReadReque
 func (r *RestAPIObjectResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
 	tflog.Debug(ctx, "ModifyPlan routine called")
 
-	// Don't modify plan during resource creation or destruction
-	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+	// Don't modify plan during resource destruction, but refuse to destroy protected objects
+	if req.Plan.Raw.IsNull() {
+		if !req.State.Raw.IsNull() {
+			var state RestAPIObjectResourceModel
+			resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+			checkDestroyPolicy(state.DestroyPolicy, state.ID, "destroyed", &resp.Diagnostics)
+		}
 		return
 	}
 
@@ -421,11 +580,32 @@ func (r *RestAPIObjectResource) ModifyPlan(ctx context.Context, req resource.Mod
 	var state RestAPIObjectResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Catch path placeholders that can't be resolved and missing ids before any request is made
+	r.validatePathPlaceholders(ctx, &plan, &state, req.State.Raw.IsNull(), &resp.Diagnostics)
+	checkObjectID(&plan, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	// Don't modify plan during resource creation
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	// The object only exists behind the endpoint it was created on, so moving it means replacing it
+	endpointChanged := !plan.Endpoint.IsUnknown() && !plan.Endpoint.Equal(state.Endpoint)
+	if endpointChanged {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("endpoint"))
+		checkDestroyPolicy(state.DestroyPolicy, state.ID, "replaced", &resp.Diagnostics)
+	}
+
 	// Skip plan modification if data is unknown/null (e.g., contains computed values)
 	if plan.Data.IsUnknown() || plan.Data.IsNull() || state.Data.IsUnknown() || state.Data.IsNull() {
 		tflog.Debug(ctx, "ModifyPlan: skipping due to unknown/null data")
@@ -463,7 +643,9 @@ func (r *RestAPIObjectResource) ModifyPlan(ctx context.Context, req resource.Mod
 		}
 	} else {
 		// Normal flow: normalize null fields that server omits
-		if normalizeNullFields(planData, stateData) {
+		planMap, planIsObject := planData.(map[string]interface{})
+		stateMap, stateIsObject := stateData.(map[string]interface{})
+		if planIsObject && stateIsObject && normalizeNullFields(planMap, stateMap) {
 			normalizedJSON, err := json.Marshal(planData)
 			if err != nil {
 				resp.Diagnostics.AddError(
@@ -489,16 +671,49 @@ func (r *RestAPIObjectResource) ModifyPlan(ctx context.Context, req resource.Mod
 		for _, field := range newFields {
 			if stateValue, err := getNestedValue(stateData, field); err == nil {
 				planValue, _ := getNestedValue(planData, field)
-				if fmt.Sprintf("%v", planValue) != fmt.Sprintf("%v", stateValue) {
+				if !apiclient.JSONEqual(planValue, stateValue) {
 					resp.RequiresReplace = append(resp.RequiresReplace, path.Root("api_data").AtMapKey(field))
 				}
 			}
 		}
 	}
+	if len(resp.RequiresReplace) > 0 && !endpointChanged {
+		checkDestroyPolicy(state.DestroyPolicy, state.ID, "replaced", &resp.Diagnostics)
+	}
 
 	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
 }
 
+// checkObjectID adds an error if data is a JSON array or value but object_id is not set, as
+// the id can't be read from it with id_attribute
+func checkObjectID(plan *RestAPIObjectResourceModel, diags *diag.Diagnostics) {
+	if plan.Data.IsNull() || plan.Data.IsUnknown() || !plan.ObjectID.IsNull() {
+		return
+	}
+	var data interface{}
+	if err := apiclient.DecodeJSON([]byte(plan.Data.ValueString()), &data); err != nil {
+		return
+	}
+	if _, isObject := data.(map[string]interface{}); isObject {
+		return
+	}
+	diags.AddError(
+		"Missing Object ID",
+		"The data is a JSON array or value rather than an object, so the object's id cannot be read from it with id_attribute. Set object_id to the object's id.",
+	)
+}
+
+// checkDestroyPolicy adds an error if the object is protected by its destroy_policy
+func checkDestroyPolicy(policy types.String, id types.String, action string, diags *diag.Diagnostics) {
+	if policy.ValueString() != apiclient.DestroyPolicyProtect {
+		return
+	}
+	diags.AddError(
+		"Protected Object",
+		fmt.Sprintf("The object with id '%s' would be %s, but its destroy_policy is '%s'. Set destroy_policy to another value and apply it first to allow this.", id.ValueString(), action, apiclient.DestroyPolicyProtect),
+	)
+}
+
 func (r *RestAPIObjectResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var plan RestAPIObjectResourceModel
 	var state RestAPIObjectResourceModel
@@ -517,6 +732,14 @@ func (r *RestAPIObjectResource) Update(ctx context.Context, req resource.UpdateR
 
 	tflog.Debug(ctx, "Update routine called", map[string]interface{}{"object": plan})
 
+	timeout, diags := plan.Timeouts.Update(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withOperationTimeout(ctx, timeout)
+	defer cancel()
+
 	client, err := r.providerData.GetClient()
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -526,6 +749,11 @@ func (r *RestAPIObjectResource) Update(ctx context.Context, req resource.UpdateR
 		return
 	}
 
+	// Path placeholders may refer to api_data, which is only known from the prior state
+	if plan.APIResponse.IsUnknown() {
+		plan.APIResponse = state.APIResponse
+	}
+
 	obj, err := makeAPIObject(ctx, client, plan.ID.ValueString(), &plan)
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -535,10 +763,23 @@ func (r *RestAPIObjectResource) Update(ctx context.Context, req resource.UpdateR
 		return
 	}
 
+	// Update strategies other than full send the difference from the prior state
+	configuredData, diags := req.Private.GetKey(ctx, privateConfiguredData)
+	resp.Diagnostics.Append(diags...)
+	if !state.Data.IsNull() && !state.Data.IsUnknown() {
+		prior, err := updatePriorData(state.Data.ValueString(), string(configuredData), plan.Data.ValueString())
+		if err == nil {
+			err = obj.SetPriorData(prior)
+		}
+		if err != nil {
+			tflog.Warn(ctx, "Could not parse prior data, the full data will be sent", map[string]interface{}{"error": err})
+		}
+	}
+
 	err = obj.UpdateObject(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Error Updating API Object",
+			apiErrorSummary("Error Updating API Object", err),
 			fmt.Sprintf("Could not update API object: %s", err.Error()),
 		)
 
@@ -553,6 +794,8 @@ func (r *RestAPIObjectResource) Update(ctx context.Context, req resource.UpdateR
 		if apiResponse := obj.GetApiResponse(); len(apiResponse) > 0 {
 			plan.Data = jsontypes.NewNormalizedValue(apiResponse)
 		}
+	} else {
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, privateConfiguredData, []byte(plan.Data.ValueString()))...)
 	}
 
 	setResourceModelData(ctx, obj, &plan, &resp.Diagnostics)
@@ -572,6 +815,14 @@ func (r *RestAPIObjectResource) Delete(ctx context.Context, req resource.DeleteR
 
 	tflog.Debug(ctx, "Delete routine called", map[string]interface{}{"state": state})
 
+	timeout, diags := state.Timeouts.Delete(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withOperationTimeout(ctx, timeout)
+	defer cancel()
+
 	client, err := r.providerData.GetClient()
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -593,7 +844,7 @@ func (r *RestAPIObjectResource) Delete(ctx context.Context, req resource.DeleteR
 	err = obj.DeleteObject(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Error Deleting API Object",
+			apiErrorSummary("Error Deleting API Object", err),
 			fmt.Sprintf("Could not delete API object: %s", err.Error()),
 		)
 		return
@@ -601,32 +852,65 @@ func (r *RestAPIObjectResource) Delete(ctx context.Context, req resource.DeleteR
 
 }
 
-// resourceRestAPIImport imports an existing API object into Terraform state.
-// Since there is nothing in the ResourceData structure other
-// than the "id" passed on the command line, we have to use an opinionated
-// view of the API paths to figure out how to read that object
-// from the API
-func (r *RestAPIObjectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	input := req.ID
+// importID is the JSON form of an import ID, for objects that can't be described by
+// /<path>/<id>, such as those with a composite id
+type importID struct {
+	Path         string   `json:"path"`
+	ID           string   `json:"id"`
+	Endpoint     string   `json:"endpoint"`
+	ReadPath     string   `json:"read_path"`
+	IDAttributes []string `json:"id_attributes"`
+	IDSeparator  string   `json:"id_separator"`
+
+	// ReadPatch is the read_patch to read the object with, as a JSON array
+	ReadPatch json.RawMessage `json:"read_patch"`
+
+	// ResponseEnvelopeKey is the path of the object in the read response
+	ResponseEnvelopeKey string `json:"response_envelope_key"`
+}
+
+// parseImportID parses an import ID, which is either /<full path from server root>/<object id>
+// or a JSON object with the fields of importID
+func parseImportID(id string) (*importID, error) {
+	if strings.HasPrefix(strings.TrimSpace(id), "{") {
+		var parsed importID
+		if err := json.Unmarshal([]byte(id), &parsed); err != nil {
+			return nil, fmt.Errorf("invalid JSON import ID '%s': %w", id, err)
+		}
+		if parsed.Path == "" || parsed.ID == "" {
+			return nil, fmt.Errorf("invalid JSON import ID '%s' - path and id must be set", id)
+		}
+		return &parsed, nil
+	}
 
 	// Remove leading and trailing slash if present
-	input = strings.TrimPrefix(input, "/")
+	input := strings.TrimPrefix(id, "/")
 	input = strings.TrimSuffix(input, "/")
 
 	n := strings.LastIndex(input, "/")
 	if n == -1 {
-		resp.Diagnostics.AddError(
-			"Invalid Import ID",
-			fmt.Sprintf("Invalid path to import api_object '%s' - must be /<full path from server root>/<object id>", req.ID),
-		)
+		return nil, fmt.Errorf("invalid path to import api_object '%s' - must be /<full path from server root>/<object id>", id)
+	}
+
+	// Add leading slash back to path
+	return &importID{Path: fmt.Sprintf("/%s", input[0:n]), ID: input[n+1:]}, nil
+}
+
+// resourceRestAPIImport imports an existing API object into Terraform state.
+// Since there is nothing in the ResourceData structure other
+// than the "id" passed on the command line, we have to use an opinionated
+// view of the API paths to figure out how to read that object
+// from the API
+func (r *RestAPIObjectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	input, err := parseImportID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import ID", err.Error())
 		return
 	}
 
 	data := RestAPIObjectResourceModel{
-		ObjectID: types.StringValue(input[n+1:]),
-
-		// Add leading slash back to path
-		Path: types.StringValue(fmt.Sprintf("/%s", input[0:n])),
+		ObjectID: types.StringValue(input.ID),
+		Path:     types.StringValue(input.Path),
 
 		// Troubleshooting is hard enough. Emit log messages so TF_LOG
 		// has useful information in case an import isn't working
@@ -634,6 +918,41 @@ func (r *RestAPIObjectResource) ImportState(ctx context.Context, req resource.Im
 
 		ForceNew:        types.ListNull(types.StringType),
 		IgnoreChangesTo: types.ListNull(types.StringType),
+		IDAttributes:    types.ListNull(types.StringType),
+
+		Timeouts: timeouts.Value{Object: types.ObjectNull(map[string]attr.Type{
+			"create": types.StringType,
+			"read":   types.StringType,
+			"update": types.StringType,
+			"delete": types.StringType,
+		})},
+	}
+
+	// The object is read from the named endpoint rather than the provider's uri
+	if input.Endpoint != "" {
+		data.Endpoint = types.StringValue(input.Endpoint)
+	}
+	if input.ReadPath != "" {
+		data.ReadPath = types.StringValue(input.ReadPath)
+	}
+	// The id is split into its parts, so they can be used in the read path
+	if len(input.IDAttributes) > 0 {
+		var diags diag.Diagnostics
+		data.IDAttributes, diags = types.ListValueFrom(ctx, types.StringType, input.IDAttributes)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	if input.IDSeparator != "" {
+		data.IDSeparator = types.StringValue(input.IDSeparator)
+	}
+	// The object is stored as read_patch reshapes it, so it matches the configuration
+	if len(input.ReadPatch) > 0 {
+		data.ReadPatch = jsontypes.NewNormalizedValue(string(input.ReadPatch))
+	}
+	if input.ResponseEnvelopeKey != "" {
+		data.ResponseEnvelopeKey = types.StringValue(input.ResponseEnvelopeKey)
 	}
 
 	client, err := r.providerData.GetClient()
@@ -659,7 +978,7 @@ func (r *RestAPIObjectResource) ImportState(ctx context.Context, req resource.Im
 	err = obj.ReadObject(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Error Reading API Object",
+			apiErrorSummary("Error Reading API Object", err),
 			fmt.Sprintf("Could not read API object: %s", err.Error()),
 		)
 		return
@@ -676,29 +995,41 @@ func makeAPIObject(ctx context.Context, client *apiclient.APIClient, id string,
 	tflog.Debug(ctx, "makeAPIObject routine called", map[string]interface{}{"id": id, "path": model.Path.ValueString()})
 
 	opts := &apiclient.APIObjectOpts{
-		Path:  model.Path.ValueString(),
-		Data:  model.Data.ValueString(),
-		Debug: model.Debug.ValueBool(),
+		Path:     model.Path.ValueString(),
+		Endpoint: existingOrDefaultString(model.Endpoint, ""),
+		Data:     model.Data.ValueString(),
+		Debug:    model.Debug.ValueBool(),
 
 		// Allow override of provider-level attributes
 		IDAttribute: existingOrProviderOrDefaultString(model.IDAttribute, client.Opts.IDAttribute, ""),
 
 		CreatePath:   existingOrDefaultString(model.CreatePath, ""),
 		CreateMethod: existingOrProviderOrDefaultString(model.CreateMethod, client.Opts.CreateMethod, "POST"),
+		OnConflict:   existingOrDefaultString(model.OnConflict, ""),
 
 		ReadPath:   existingOrDefaultString(model.ReadPath, ""),
 		ReadMethod: existingOrProviderOrDefaultString(model.ReadMethod, client.Opts.ReadMethod, "GET"),
 		ReadData:   model.ReadData.ValueString(),
 
-		UpdatePath:   existingOrDefaultString(model.UpdatePath, ""),
-		UpdateMethod: existingOrProviderOrDefaultString(model.UpdateMethod, client.Opts.UpdateMethod, "PUT"),
-		UpdateData:   model.UpdateData.ValueString(),
+		UpdatePath:     existingOrDefaultString(model.UpdatePath, ""),
+		UpdateMethod:   existingOrProviderOrDefaultString(model.UpdateMethod, client.Opts.UpdateMethod, "PUT"),
+		UpdateData:     model.UpdateData.ValueString(),
+		UpdateStrategy: existingOrDefaultString(model.UpdateStrategy, ""),
 
 		DestroyPath:   existingOrDefaultString(model.DestroyPath, ""),
 		DestroyMethod: existingOrProviderOrDefaultString(model.DestroyMethod, client.Opts.DestroyMethod, "DELETE"),
 		DestroyData:   model.DestroyData.ValueString(),
+		DestroyPolicy: existingOrDefaultString(model.DestroyPolicy, ""),
+		ResetData:     model.ResetData.ValueString(),
+
+		ReadPatch:  model.ReadPatch.ValueString(),
+		WritePatch: model.WritePatch.ValueString(),
+
+		ResponseEnvelopeKey: existingOrProviderOrDefaultString(model.ResponseEnvelopeKey, client.Opts.ResponseEnvelopeKey, ""),
+		RequestEnvelopeKey:  existingOrProviderOrDefaultString(model.RequestEnvelopeKey, client.Opts.RequestEnvelopeKey, ""),
 
 		QueryString: existingOrDefaultString(model.QueryString, ""),
+		APIResponse: existingOrDefaultString(model.APIResponse, ""),
 	}
 
 	// Wire up read_search if configured
@@ -710,6 +1041,15 @@ func makeAPIObject(ctx context.Context, client *apiclient.APIClient, id string,
 		if !model.ReadSearch.SearchValue.IsNull() && !model.ReadSearch.SearchValue.IsUnknown() {
 			readSearch["search_value"] = model.ReadSearch.SearchValue.ValueString()
 		}
+		if !model.ReadSearch.SearchFilter.IsNull() && !model.ReadSearch.SearchFilter.IsUnknown() {
+			readSearch["search_filter"] = model.ReadSearch.SearchFilter.ValueString()
+		}
+		if readSearch["search_filter"] == "" && (readSearch["search_key"] == "" || readSearch["search_value"] == "") {
+			return nil, fmt.Errorf("read_search requires search_filter or both search_key and search_value")
+		}
+		if !model.ReadSearch.MatchPolicy.IsNull() && !model.ReadSearch.MatchPolicy.IsUnknown() {
+			readSearch["match_policy"] = model.ReadSearch.MatchPolicy.ValueString()
+		}
 		if !model.ReadSearch.ResultsKey.IsNull() && !model.ReadSearch.ResultsKey.IsUnknown() {
 			readSearch["results_key"] = model.ReadSearch.ResultsKey.ValueString()
 		}
@@ -725,6 +1065,42 @@ func makeAPIObject(ctx context.Context, client *apiclient.APIClient, id string,
 		opts.ReadSearch = readSearch
 	}
 
+	if !model.IDAttributes.IsNull() && !model.IDAttributes.IsUnknown() {
+		if !model.IDAttribute.IsNull() {
+			return nil, fmt.Errorf("id_attribute and id_attributes cannot both be set")
+		}
+		if diags := model.IDAttributes.ElementsAs(ctx, &opts.IDAttributes, false); diags.HasError() {
+			return nil, fmt.Errorf("invalid id_attributes: %v", diags)
+		}
+		opts.IDSeparator = existingOrDefaultString(model.IDSeparator, apiclient.DefaultIDSeparator)
+	}
+
+	if model.WaitForDeletion != nil {
+		wait := &apiclient.WaitForDeletion{
+			StateKey:   existingOrDefaultString(model.WaitForDeletion.StateKey, ""),
+			StateValue: existingOrDefaultString(model.WaitForDeletion.StateValue, ""),
+		}
+		var err error
+		if interval := existingOrDefaultString(model.WaitForDeletion.Interval, ""); interval != "" {
+			if wait.Interval, err = time.ParseDuration(interval); err != nil {
+				return nil, fmt.Errorf("invalid wait_for_deletion interval '%s': %w", interval, err)
+			}
+		}
+		if timeout := existingOrDefaultString(model.WaitForDeletion.Timeout, ""); timeout != "" {
+			if wait.Timeout, err = time.ParseDuration(timeout); err != nil {
+				return nil, fmt.Errorf("invalid wait_for_deletion timeout '%s': %w", timeout, err)
+			}
+		}
+		opts.WaitForDeletion = wait
+	}
+
+	// Patches are sent with PATCH unless the resource says otherwise. Reset data is a whole
+	// object, so it is still sent with the configured update method.
+	opts.ResetMethod = opts.UpdateMethod
+	if opts.UpdateStrategy != "" && opts.UpdateStrategy != apiclient.UpdateStrategyFull && (model.UpdateMethod.IsNull() || model.UpdateMethod.IsUnknown()) {
+		opts.UpdateMethod = "PATCH"
+	}
+
 	// Allow user to specify the ID manually
 	if !model.ObjectID.IsNull() && !model.ObjectID.IsUnknown() {
 		opts.ID = model.ObjectID.ValueString()
@@ -736,6 +1112,82 @@ func makeAPIObject(ctx context.Context, client *apiclient.APIClient, id string,
 	return apiclient.NewAPIObject(client, opts)
 }
 
+// validatePathPlaceholders checks that every placeholder in the object's paths resolves
+// from data, api_data or the provider's path_variables. When creating, api_data isn't
+// known yet, so only create_path is checked. Paths or data that aren't known yet are skipped.
+func (r *RestAPIObjectResource) validatePathPlaceholders(ctx context.Context, plan *RestAPIObjectResourceModel, state *RestAPIObjectResourceModel, creating bool, diags *diag.Diagnostics) {
+	if r.providerData == nil || r.providerData.opts == nil || plan.Data.IsUnknown() || plan.Path.IsUnknown() {
+		return
+	}
+
+	var data, apiData map[string]interface{}
+	if !plan.Data.IsNull() {
+		if err := apiclient.DecodeJSON([]byte(plan.Data.ValueString()), &data); err != nil {
+			return
+		}
+	}
+	if !creating && !state.APIResponse.IsNull() && !state.APIResponse.IsUnknown() {
+		_ = apiclient.DecodeJSON([]byte(state.APIResponse.ValueString()), &apiData)
+	}
+
+	// With object_id set, the parts of a composite id are known without data
+	var idParts []string
+	if !plan.ObjectID.IsNull() && !plan.ObjectID.IsUnknown() && !plan.IDAttributes.IsNull() && !plan.IDAttributes.IsUnknown() {
+		diags.Append(plan.IDAttributes.ElementsAs(ctx, &idParts, false)...)
+	}
+
+	attrs := []string{"create_path"}
+	paths := []types.String{plan.CreatePath}
+	if !creating {
+		attrs = []string{"read_path", "update_path", "destroy_path"}
+		paths = []types.String{plan.ReadPath, plan.UpdatePath, plan.DestroyPath}
+	}
+
+	checked := map[string]bool{}
+	for i, p := range paths {
+		attr := attrs[i]
+		if p.IsUnknown() || plan.QueryString.IsUnknown() {
+			continue
+		}
+		if p.IsNull() {
+			attr = "path"
+			p = plan.Path
+		}
+		template := p.ValueString()
+		if q := existingOrDefaultString(plan.QueryString, ""); q != "" {
+			template = fmt.Sprintf("%s?%s", template, q)
+		}
+		if checked[template] {
+			continue
+		}
+		checked[template] = true
+
+		for _, placeholder := range apiclient.PathPlaceholders(template) {
+			if !slices.Contains(idParts, placeholder.Name) && !pathPlaceholderResolves(ctx, placeholder.Name, data, apiData, r.providerData.opts.PathVariables) {
+				diags.AddError(
+					"Invalid Path Placeholder",
+					fmt.Sprintf("The placeholder '{%s}' in %s '%s' does not resolve to a string, number or boolean in data, api_data or the provider's path_variables.", placeholder.Name, attr, template),
+				)
+			}
+		}
+	}
+}
+
+// pathPlaceholderResolves reports whether a path placeholder has a value, looked up the same way the API client does
+func pathPlaceholderResolves(ctx context.Context, name string, data map[string]interface{}, apiData map[string]interface{}, vars map[string]string) bool {
+	if name == "id" {
+		return true
+	}
+	if _, err := apiclient.GetStringAtKey(ctx, data, name); err == nil {
+		return true
+	}
+	if _, err := apiclient.GetStringAtKey(ctx, apiData, name); err == nil {
+		return true
+	}
+	_, ok := vars[name]
+	return ok
+}
+
 func setResourceModelData(ctx context.Context, obj *apiclient.APIObject, data *RestAPIObjectResourceModel, diag *diag.Diagnostics) {
 	data.ID = types.StringValue(obj.ID)
 	data.APIResponse = types.StringValue(obj.GetApiResponse())
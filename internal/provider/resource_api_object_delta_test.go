@@ -1,9 +1,12 @@
 package provider
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"testing"
+
+	"github.com/Mastercard/terraform-provider-restapi/internal/apiclient"
 )
 
 // Creating a type alias to save some typing in the test cases
@@ -455,6 +458,91 @@ func TestHasDeltaModifiedResource(t *testing.T) {
 	}
 }
 
+// TestHasDeltaJSONPointer tests ignoring fields given as JSON Pointers, including keys
+// with dots and slashes and elements of lists
+func TestHasDeltaJSONPointer(t *testing.T) {
+	recorded := MapAny{
+		"metadata": MapAny{"labels": MapAny{"app.kubernetes.io/version": "1"}},
+		"rules":    []interface{}{MapAny{"name": "allow", "hits": 1}, MapAny{"name": "deny", "hits": 2}},
+		"tags":     []interface{}{"red", "blue"},
+	}
+	actual := MapAny{
+		"metadata": MapAny{"labels": MapAny{"app.kubernetes.io/version": "2"}},
+		"rules":    []interface{}{MapAny{"name": "allow", "hits": 5}, MapAny{"name": "deny", "hits": 7}},
+		"tags":     []interface{}{"red", "green"},
+	}
+
+	ignoreList := []string{"/metadata/labels/app.kubernetes.io~1version", "/rules/0/hits", "rules.1.hits", "/tags/1"}
+	modified, hasChanges := getDelta(recorded, actual, ignoreList, false)
+	if hasChanges {
+		t.Errorf("delta_checker_test.go: Unexpected changes with ignored fields: %v", modified)
+	}
+	if !reflect.DeepEqual(recorded, modified) {
+		t.Errorf("delta_checker_test.go: Unexpected delta: expected %v but got %v", recorded, modified)
+	}
+
+	modified, hasChanges = getDelta(recorded, actual, []string{"/metadata/labels/app.kubernetes.io~1version", "/rules/0/hits", "/tags/1"}, false)
+	if !hasChanges {
+		t.Errorf("delta_checker_test.go: Expected a change in /rules/1/hits")
+	}
+	expected := []interface{}{MapAny{"name": "allow", "hits": 1}, MapAny{"name": "deny", "hits": 7}}
+	if !reflect.DeepEqual(expected, modified.(MapAny)["rules"]) {
+		t.Errorf("delta_checker_test.go: Unexpected rules: expected %v but got %v", expected, modified.(MapAny)["rules"])
+	}
+}
+
+func TestHasDeltaJSONNumbers(t *testing.T) {
+	recorded := MapAny{}
+	actual := MapAny{}
+	if err := apiclient.DecodeJSON([]byte(`{"id":9007199254740993,"size":1,"ratio":0.5,"sizes":[1,2]}`), &recorded); err != nil {
+		t.Fatal(err)
+	}
+	if err := apiclient.DecodeJSON([]byte(`{"id":9007199254740993,"size":1.0,"ratio":5e-1,"sizes":[1,2.0]}`), &actual); err != nil {
+		t.Fatal(err)
+	}
+
+	modified, hasChanges := getDelta(recorded, actual, nil, false)
+	if hasChanges {
+		t.Errorf("delta_checker_test.go: Unexpected changes between equal numbers: %v", modified)
+	}
+
+	if err := apiclient.DecodeJSON([]byte(`{"id":9007199254740992,"size":1,"ratio":0.5,"sizes":[1,2]}`), &actual); err != nil {
+		t.Fatal(err)
+	}
+	modified, hasChanges = getDelta(recorded, actual, nil, false)
+	if !hasChanges {
+		t.Errorf("delta_checker_test.go: Expected a change between 9007199254740993 and 9007199254740992")
+	}
+	if modified.(MapAny)["id"] != json.Number("9007199254740992") {
+		t.Errorf("delta_checker_test.go: Unexpected id: %v", modified.(MapAny)["id"])
+	}
+}
+
+func TestHasDeltaDocuments(t *testing.T) {
+	recorded := []interface{}{"10.0.0.0/8", MapAny{"cidr": "192.168.0.0/16", "hits": 1}}
+	actual := []interface{}{"10.0.0.0/8", MapAny{"cidr": "192.168.0.0/16", "hits": 9}}
+
+	modified, hasChanges := getDelta(recorded, actual, nil, false)
+	if !hasChanges || !reflect.DeepEqual(actual, modified) {
+		t.Errorf("delta_checker_test.go: Expected the actual list as a change, got %v (%v)", modified, hasChanges)
+	}
+
+	modified, hasChanges = getDelta(recorded, actual, []string{"/1/hits"}, false)
+	if hasChanges || !reflect.DeepEqual(recorded, modified) {
+		t.Errorf("delta_checker_test.go: Unexpected changes with ignored fields: %v", modified)
+	}
+
+	if _, hasChanges = getDelta(json.Number("42"), json.Number("42.0"), nil, false); hasChanges {
+		t.Errorf("delta_checker_test.go: Unexpected changes between equal values")
+	}
+	if modified, hasChanges = getDelta("on", "off", nil, false); !hasChanges || modified != "off" {
+		t.Errorf("delta_checker_test.go: Expected 'off' as a change, got %v (%v)", modified, hasChanges)
+	}
+	if _, hasChanges = getDelta(MapAny{"cidr": "10.0.0.0/8"}, []interface{}{"10.0.0.0/8"}, nil, false); !hasChanges {
+		t.Errorf("delta_checker_test.go: Expected a change from an object to a list")
+	}
+}
+
 func TestIgnoreServerAdditions(t *testing.T) {
 	testCases := []struct {
 		name                  string
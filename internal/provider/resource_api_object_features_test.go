@@ -1,12 +1,14 @@
 package provider
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/Mastercard/terraform-provider-restapi/fakeserver"
 	"github.com/Mastercard/terraform-provider-restapi/internal/apiclient"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
 
 // TestAccRestApiObject_IgnoreChangesTo tests the ignore_changes_to feature
@@ -191,6 +193,74 @@ resource "restapi_object" "Test" {
 	})
 }
 
+// TestAccRestApiObject_EndpointChange tests that moving an object to another endpoint recreates it there
+func TestAccRestApiObject_EndpointChange(t *testing.T) {
+	debug := false
+
+	primaryObjects := make(map[string]map[string]interface{})
+	primary := fakeserver.NewFakeServer(8132, primaryObjects, map[string]string{}, true, debug, "")
+	defer primary.Shutdown()
+	secondaryObjects := make(map[string]map[string]interface{})
+	secondary := fakeserver.NewFakeServer(8133, secondaryObjects, map[string]string{}, true, debug, "")
+	defer secondary.Shutdown()
+
+	config := func(endpoint string) string {
+		return fmt.Sprintf(`
+provider "restapi" {
+  uri = "http://127.0.0.1:8132"
+  endpoints = {
+    secondary = {
+      uri = "http://127.0.0.1:8133"
+    }
+  }
+}
+
+resource "restapi_object" "Test" {
+  path = "/api/objects"
+  data = "{ \"id\": \"moved1\", \"name\": \"Test\" }"
+  %s
+}
+`, endpoint)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck: func() {
+			primary.StartInBackground()
+			secondary.StartInBackground()
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config(""),
+				Check: func(*terraform.State) error {
+					if _, ok := primaryObjects["moved1"]; !ok {
+						return fmt.Errorf("object was not created on the provider's uri")
+					}
+					return nil
+				},
+			},
+			{
+				Config: config(`endpoint = "secondary"`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("restapi_object.Test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: func(*terraform.State) error {
+					if _, ok := primaryObjects["moved1"]; ok {
+						return fmt.Errorf("object was not deleted from the provider's uri")
+					}
+					if _, ok := secondaryObjects["moved1"]; !ok {
+						return fmt.Errorf("object was not created on the endpoint")
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
 // TestAccRestApiObject_DestroyData tests the destroy_data feature
 func TestAccRestApiObject_DestroyData(t *testing.T) {
 	debug := false
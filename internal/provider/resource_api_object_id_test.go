@@ -0,0 +1,165 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Mastercard/terraform-provider-restapi/internal/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImportID(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		want    *importID
+		wantErr string
+	}{
+		{
+			name: "path",
+			id:   "/api/objects/1234",
+			want: &importID{Path: "/api/objects", ID: "1234"},
+		},
+		{
+			name: "path_trailing_slash",
+			id:   "api/objects/1234/",
+			want: &importID{Path: "/api/objects", ID: "1234"},
+		},
+		{
+			name: "json",
+			id:   `{"path": "/records", "read_path": "/zones/{zone}/records/{name}", "id": "example.com|www", "id_attributes": ["zone", "name"], "id_separator": "|"}`,
+			want: &importID{Path: "/records", ReadPath: "/zones/{zone}/records/{name}", ID: "example.com|www", IDAttributes: []string{"zone", "name"}, IDSeparator: "|"},
+		},
+		{
+			name: "json_read_patch",
+			id:   `{"path": "/objects", "id": "1", "read_patch": [{"op": "remove", "path": "/status"}]}`,
+			want: &importID{Path: "/objects", ID: "1", ReadPatch: json.RawMessage(`[{"op": "remove", "path": "/status"}]`)},
+		},
+		{
+			name: "json_response_envelope_key",
+			id:   `{"path": "/objects", "id": "1", "response_envelope_key": "data"}`,
+			want: &importID{Path: "/objects", ID: "1", ResponseEnvelopeKey: "data"},
+		},
+		{
+			name: "json_endpoint",
+			id:   `{"path": "/objects", "id": "1", "endpoint": "eu"}`,
+			want: &importID{Path: "/objects", ID: "1", Endpoint: "eu"},
+		},
+		{
+			name:    "no_path",
+			id:      "1234",
+			wantErr: "must be /<full path from server root>/<object id>",
+		},
+		{
+			name:    "json_without_id",
+			id:      `{"path": "/records"}`,
+			wantErr: "path and id must be set",
+		},
+		{
+			name:    "bad_json",
+			id:      `{"path": "/records"`,
+			wantErr: "invalid JSON import ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseImportID(tt.id)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMakeAPIObject_IDAttributes(t *testing.T) {
+	ctx := context.Background()
+
+	client, err := apiclient.NewAPIClient(&apiclient.APIClientOpt{
+		URI:         "http://localhost:8080",
+		Timeout:     2,
+		IDAttribute: "id",
+	})
+	require.NoError(t, err)
+
+	idAttributes, diags := types.ListValueFrom(ctx, types.StringType, []string{"zone", "name"})
+	require.False(t, diags.HasError())
+
+	obj, err := makeAPIObject(ctx, client, "", &RestAPIObjectResourceModel{
+		Path:         types.StringValue("/zones/{zone}/records"),
+		Data:         jsontypes.NewNormalizedValue(`{"zone":"example.com","name":"www"}`),
+		IDAttributes: idAttributes,
+		IDSeparator:  types.StringValue("/"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/www", obj.ID)
+
+	_, err = makeAPIObject(ctx, client, "", &RestAPIObjectResourceModel{
+		Path:         types.StringValue("/zones/{zone}/records"),
+		Data:         jsontypes.NewNormalizedValue(`{"zone":"example.com","name":"www"}`),
+		IDAttribute:  types.StringValue("name"),
+		IDAttributes: idAttributes,
+	})
+	assert.ErrorContains(t, err, "id_attribute and id_attributes cannot both be set")
+}
+
+func TestMakeAPIObject_EnvelopeKeys(t *testing.T) {
+	ctx := context.Background()
+
+	client, err := apiclient.NewAPIClient(&apiclient.APIClientOpt{
+		URI:                 "http://localhost:8080",
+		Timeout:             2,
+		ResponseEnvelopeKey: "data",
+		RequestEnvelopeKey:  "item",
+	})
+	require.NoError(t, err)
+
+	obj, err := makeAPIObject(ctx, client, "", &RestAPIObjectResourceModel{
+		Path:               types.StringValue("/api/objects"),
+		Data:               jsontypes.NewNormalizedValue(`{"id":"1"}`),
+		RequestEnvelopeKey: types.StringValue("object/spec"),
+	})
+	require.NoError(t, err)
+	assert.Contains(t, obj.String(), "response_envelope_key: data\n")
+	assert.Contains(t, obj.String(), "request_envelope_key: object/spec\n")
+}
+
+func TestMakeAPIObject_ResetMethod(t *testing.T) {
+	ctx := context.Background()
+
+	var method string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+	}))
+	defer server.Close()
+
+	client, err := apiclient.NewAPIClient(&apiclient.APIClientOpt{
+		URI:         server.URL,
+		Timeout:     2,
+		IDAttribute: "id",
+	})
+	require.NoError(t, err)
+
+	// Updates are sent as patches, but the reset data replaces the whole object
+	obj, err := makeAPIObject(ctx, client, "", &RestAPIObjectResourceModel{
+		Path:           types.StringValue("/api/settings"),
+		Data:           jsontypes.NewNormalizedValue(`{"id":"1","enabled":true}`),
+		UpdateStrategy: types.StringValue(apiclient.UpdateStrategyMergePatch),
+		DestroyPolicy:  types.StringValue(apiclient.DestroyPolicyReset),
+		ResetData:      jsontypes.NewNormalizedValue(`{"id":"1","enabled":false}`),
+	})
+	require.NoError(t, err)
+	assert.Contains(t, obj.String(), "update_method: PATCH\n")
+
+	require.NoError(t, obj.DeleteObject(ctx))
+	assert.Equal(t, "PUT", method)
+}
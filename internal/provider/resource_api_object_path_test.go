@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Mastercard/terraform-provider-restapi/internal/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePathPlaceholders(t *testing.T) {
+	r := &RestAPIObjectResource{providerData: &ProviderData{opts: &apiclient.APIClientOpt{
+		PathVariables: map[string]string{"tenant": "acme"},
+	}}}
+
+	tests := []struct {
+		name      string
+		plan      RestAPIObjectResourceModel
+		state     RestAPIObjectResourceModel
+		creating  bool
+		wantError string
+	}{
+		{
+			name: "resolves_from_data_and_provider",
+			plan: RestAPIObjectResourceModel{
+				Path: types.StringValue("/tenants/{tenant}/orgs/{org_id}/teams/{+id}"),
+				Data: jsontypes.NewNormalizedValue(`{"org_id":"org-1"}`),
+			},
+			creating: true,
+		},
+		{
+			name: "unresolved_on_create",
+			plan: RestAPIObjectResourceModel{
+				Path: types.StringValue("/orgs/{org_id}/teams"),
+				Data: jsontypes.NewNormalizedValue(`{"id":"1"}`),
+			},
+			creating:  true,
+			wantError: "The placeholder '{org_id}' in path '/orgs/{org_id}/teams'",
+		},
+		{
+			name: "api_data_not_checked_on_create",
+			plan: RestAPIObjectResourceModel{
+				Path:       types.StringValue("/api/objects"),
+				CreatePath: types.StringValue("/api/objects"),
+				ReadPath:   types.StringValue("/api/objects/{uuid}"),
+				Data:       jsontypes.NewNormalizedValue(`{"id":"1"}`),
+			},
+			creating: true,
+		},
+		{
+			name: "resolves_from_api_data",
+			plan: RestAPIObjectResourceModel{
+				Path:     types.StringValue("/api/objects"),
+				ReadPath: types.StringValue("/api/objects/{uuid}"),
+				Data:     jsontypes.NewNormalizedValue(`{"id":"1"}`),
+			},
+			state: RestAPIObjectResourceModel{
+				APIResponse: types.StringValue(`{"id":"1","uuid":"abc"}`),
+			},
+		},
+		{
+			name: "unresolved_query_string",
+			plan: RestAPIObjectResourceModel{
+				Path:        types.StringValue("/api/objects"),
+				QueryString: types.StringValue("version={version}"),
+				Data:        jsontypes.NewNormalizedValue(`{"id":"1"}`),
+			},
+			state: RestAPIObjectResourceModel{
+				APIResponse: types.StringValue(`{"id":"1"}`),
+			},
+			wantError: "The placeholder '{version}' in path '/api/objects?version={version}'",
+		},
+		{
+			name: "unknown_data_skipped",
+			plan: RestAPIObjectResourceModel{
+				Path: types.StringValue("/orgs/{org_id}/teams"),
+				Data: jsontypes.NewNormalizedUnknown(),
+			},
+			creating: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			r.validatePathPlaceholders(context.Background(), &tt.plan, &tt.state, tt.creating, &diags)
+			if tt.wantError == "" {
+				assert.False(t, diags.HasError(), "unexpected errors: %v", diags)
+				return
+			}
+			if assert.Len(t, diags.Errors(), 1) {
+				assert.Contains(t, diags.Errors()[0].Detail(), tt.wantError)
+			}
+		})
+	}
+}
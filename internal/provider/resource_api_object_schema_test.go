@@ -161,6 +161,198 @@ func TestResourceObject_valid(t *testing.T) {
 				debug = true
 			}`,
 
+		"with_endpoint": `
+			provider "restapi" {
+               	uri = "http://localhost:8080/"
+				endpoints = {
+					v2 = {
+						uri = "http://localhost:8080/v2"
+					}
+				}
+			}
+			resource "restapi_object" "test" {
+				path = "/api/objects"
+				endpoint = "v2"
+				data = jsonencode({
+					name = "test"
+				})
+			}`,
+		"with_path_placeholders": `
+			provider "restapi" {
+               	uri = "http://localhost:8080/"
+				path_variables = {
+					tenant = "acme"
+				}
+			}
+			resource "restapi_object" "test" {
+				path = "/tenants/{tenant}/orgs/{org_id}/teams"
+				read_path = "/teams/{+id}"
+				data = jsonencode({
+					id = "123"
+					org_id = "org 1"
+				})
+			}`,
+		"with_id_attributes": `
+			provider "restapi" {
+               	uri = "http://localhost:8080/"
+			}
+			resource "restapi_object" "test" {
+				path = "/zones/{zone}/records"
+				read_path = "/zones/{zone}/records/{name}"
+				id_attributes = ["zone", "name"]
+				id_separator = "/"
+				data = jsonencode({
+					zone = "example.com"
+					name = "www"
+				})
+			}`,
+		"with_on_conflict": `
+			provider "restapi" {
+               	uri = "http://localhost:8080/"
+			}
+			resource "restapi_object" "test" {
+				path = "/api/objects"
+				on_conflict = "adopt_and_update"
+				data = jsonencode({
+					name = "test"
+				})
+				read_search = {
+					search_key = "name"
+					search_value = "test"
+				}
+			}`,
+		"with_wait_for_deletion": `
+			provider "restapi" {
+               	uri = "http://localhost:8080/"
+			}
+			resource "restapi_object" "test" {
+				path = "/api/objects"
+				data = jsonencode({
+					id = "123"
+				})
+				wait_for_deletion = {
+					interval = "10s"
+					timeout = "5m"
+					state_key = "status"
+					state_value = "DELETED"
+				}
+			}`,
+		"with_destroy_policy": `
+			provider "restapi" {
+               	uri = "http://localhost:8080/"
+			}
+			resource "restapi_object" "test" {
+				path = "/api/settings"
+				object_id = "global"
+				data = jsonencode({
+					enabled = true
+				})
+				destroy_policy = "reset"
+				reset_data = jsonencode({
+					enabled = false
+				})
+			}`,
+		"with_read_write_patch": `
+			provider "restapi" {
+               	uri = "http://localhost:8080/"
+			}
+			resource "restapi_object" "test" {
+				path = "/api/objects"
+				data = jsonencode({
+					id = "123"
+					name = "test"
+				})
+				read_patch = jsonencode([
+					{ op = "move", from = "/spec/name", path = "/name" },
+					{ op = "remove", path = "/spec" },
+				])
+				write_patch = jsonencode([
+					{ op = "add", path = "/spec", value = {} },
+					{ op = "move", from = "/name", path = "/spec/name" },
+				])
+			}`,
+		"with_search_filter": `
+			provider "restapi" {
+               	uri = "http://localhost:8080/"
+			}
+			resource "restapi_object" "test" {
+				path = "/api/objects"
+				data = jsonencode({
+					name = "test"
+					env = "prod"
+				})
+				read_search = {
+					search_filter = "@.name == 'test' && @.env == 'prod'"
+				}
+			}`,
+		"with_match_policy": `
+			provider "restapi" {
+               	uri = "http://localhost:8080/"
+			}
+			resource "restapi_object" "test" {
+				path = "/api/objects"
+				data = jsonencode({
+					name = "test"
+				})
+				read_search = {
+					search_key = "name"
+					search_value = "test"
+					match_policy = "unique"
+				}
+			}`,
+		"with_envelope_keys": `
+			provider "restapi" {
+               	uri = "http://localhost:8080/"
+               	response_envelope_key = "data"
+			}
+			resource "restapi_object" "test" {
+				path = "/api/objects"
+				data = jsonencode({
+					id = "123"
+					name = "test"
+				})
+				response_envelope_key = "/data/item"
+				request_envelope_key = "item"
+			}`,
+		"with_array_data": `
+			provider "restapi" {
+               	uri = "http://localhost:8080/"
+			}
+			resource "restapi_object" "test" {
+				path = "/allowlist"
+				object_id = "allowlist"
+				create_method = "PUT"
+				read_path = "/allowlist"
+				update_path = "/allowlist"
+				data = jsonencode(["10.0.0.0/8", "192.168.0.0/16"])
+			}`,
+		"with_update_strategy": `
+			provider "restapi" {
+               	uri = "http://localhost:8080/"
+			}
+			resource "restapi_object" "test" {
+				path = "/api/objects"
+				update_strategy = "merge_patch"
+				data = jsonencode({
+					name = "test"
+				})
+			}`,
+		"with_timeouts": `
+			provider "restapi" {
+               	uri = "http://localhost:8080/"
+			}
+			resource "restapi_object" "test" {
+				path = "/api/objects"
+				data = jsonencode({
+					name = "test"
+				})
+				timeouts {
+					create = "30m"
+					read   = "1m"
+					delete = "10m"
+				}
+			}`,
+
 		"empty_json_object": `
 			provider "restapi" {
                	uri = "http://localhost:8080/"
@@ -340,6 +532,32 @@ func TestResourceObject_invalid(t *testing.T) {
 			expectError: `Inappropriate value for attribute "ignore_changes_to"`,
 		},
 
+		"unresolved_path_placeholder": {
+			config: `
+				provider "restapi" {
+					uri = "http://localhost:8080/"
+				}
+				resource "restapi_object" "test" {
+					path = "/orgs/{org_id}/teams"
+					data = jsonencode({id = "123"})
+				}
+			`,
+			expectError: `Invalid Path Placeholder`,
+		},
+
+		"array_data_without_object_id": {
+			config: `
+				provider "restapi" {
+					uri = "http://localhost:8080/"
+				}
+				resource "restapi_object" "test" {
+					path = "/allowlist"
+					data = jsonencode(["10.0.0.0/8"])
+				}
+			`,
+			expectError: `Missing Object ID`,
+		},
+
 		"unknown_attribute": {
 			config: `
 				provider "restapi" {
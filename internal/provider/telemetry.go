@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// SetupTelemetry configures OpenTelemetry export of the API client's traces and metrics.
+// Export is opt-in: traces are exported over OTLP/HTTP when OTEL_TRACES_EXPORTER=otlp or an OTLP
+// endpoint is set, and metrics when OTEL_METRICS_EXPORTER=otlp or an OTLP endpoint is set. The
+// exporters are configured by the standard OTEL_EXPORTER_OTLP_* variables, and the resource by
+// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES. The returned function flushes and stops the
+// exporters and must be called before the provider exits.
+func SetupTelemetry(ctx context.Context, version string) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return noop, nil
+	}
+
+	_, endpointSet := os.LookupEnv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	tracesEnabled := otelExporterEnabled("OTEL_TRACES_EXPORTER", "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", endpointSet)
+	metricsEnabled := otelExporterEnabled("OTEL_METRICS_EXPORTER", "OTEL_EXPORTER_OTLP_METRICS_ENDPOINT", endpointSet)
+	if !tracesEnabled && !metricsEnabled {
+		return noop, nil
+	}
+
+	// Attributes from the environment take precedence over the defaults
+	res, err := sdkresource.New(ctx,
+		sdkresource.WithTelemetrySDK(),
+		sdkresource.WithAttributes(
+			attribute.String("service.name", "terraform-provider-restapi"),
+			attribute.String("service.version", version),
+		),
+		sdkresource.WithFromEnv(),
+	)
+	if err != nil {
+		return noop, err
+	}
+
+	var shutdowns []func(context.Context) error
+	shutdown := func(ctx context.Context) error {
+		var errs []error
+		for _, fn := range shutdowns {
+			errs = append(errs, fn(ctx))
+		}
+		return errors.Join(errs...)
+	}
+
+	if tracesEnabled {
+		exporter, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return noop, err
+		}
+		tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+		otel.SetTracerProvider(tracerProvider)
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+		shutdowns = append(shutdowns, tracerProvider.Shutdown)
+	}
+
+	if metricsEnabled {
+		exporter, err := otlpmetrichttp.New(ctx)
+		if err != nil {
+			return shutdown, err
+		}
+		meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter)), sdkmetric.WithResource(res))
+		otel.SetMeterProvider(meterProvider)
+		shutdowns = append(shutdowns, meterProvider.Shutdown)
+	}
+
+	return shutdown, nil
+}
+
+// otelExporterEnabled reports whether the OTLP exporter for a signal has been asked for
+func otelExporterEnabled(exporterKey string, signalEndpointKey string, endpointSet bool) bool {
+	if exporter, ok := os.LookupEnv(exporterKey); ok {
+		return strings.EqualFold(exporter, "otlp")
+	}
+	_, signalEndpointSet := os.LookupEnv(signalEndpointKey)
+	return endpointSet || signalEndpointSet
+}
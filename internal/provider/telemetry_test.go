@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"os"
+	"testing"
+)
+
+func TestOtelExporterEnabled(t *testing.T) {
+	tests := map[string]struct {
+		env         map[string]string
+		endpointSet bool
+		expected    bool
+	}{
+		"nothing set": {
+			env:      map[string]string{},
+			expected: false,
+		},
+		"endpoint set": {
+			env:         map[string]string{},
+			endpointSet: true,
+			expected:    true,
+		},
+		"signal endpoint set": {
+			env:      map[string]string{"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "http://localhost:4318/v1/traces"},
+			expected: true,
+		},
+		"exporter otlp": {
+			env:      map[string]string{"OTEL_TRACES_EXPORTER": "otlp"},
+			expected: true,
+		},
+		"exporter none overrides endpoint": {
+			env:         map[string]string{"OTEL_TRACES_EXPORTER": "none"},
+			endpointSet: true,
+			expected:    false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("OTEL_TRACES_EXPORTER", "")
+			t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+			os.Unsetenv("OTEL_TRACES_EXPORTER")
+			os.Unsetenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+
+			if got := otelExporterEnabled("OTEL_TRACES_EXPORTER", "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", tc.endpointSet); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
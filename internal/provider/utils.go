@@ -1,16 +1,34 @@
 package provider
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
 
+	"github.com/Mastercard/terraform-provider-restapi/internal/apiclient"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 )
 
+// apiErrorSummary returns the diagnostic summary for an error returned by the API client.
+// Errors caused by the API being unavailable or the operation being interrupted (cancelled or
+// timed out) get a dedicated summary so they are easy to tell apart from errors about a
+// particular object.
+func apiErrorSummary(summary string, err error) string {
+	var interrupted *apiclient.RequestInterruptedError
+	if errors.As(err, &interrupted) {
+		return "Operation Interrupted"
+	}
+	if errors.Is(err, apiclient.ErrAPIUnavailable) {
+		return "API Unavailable"
+	}
+	return summary
+}
+
 // existingOrEnvOrDefaultString resolves a string configuration value using a three-tier precedence:
 // 1. Explicit configuration value (curVal)
 // 2. Environment variable (envKey)
@@ -135,6 +153,53 @@ func existingOrEnvOrDefaultBool(d *diag.Diagnostics, key string, curVal basetype
 	return def
 }
 
+// existingOrEnvOrDefaultStringList resolves a list of strings configuration value using the same
+// three-tier precedence, with the environment variable holding a comma-separated list.
+// A list that is unknown or has unknown elements (it depends on another resource) resolves to nil.
+func existingOrEnvOrDefaultStringList(ctx context.Context, d *diag.Diagnostics, key string, curVal basetypes.ListValue, envKey string, def []string, required bool) []string {
+	if !curVal.IsNull() {
+		return knownStringList(ctx, d, curVal)
+	}
+
+	val, ok := os.LookupEnv(envKey)
+	if ok {
+		var vals []string
+		for _, v := range strings.Split(val, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				vals = append(vals, v)
+			}
+		}
+		return vals
+	}
+
+	if required {
+		d.AddError(
+			"Missing Required Configuration",
+			fmt.Sprintf("The %s configuration value is required. You can set this value in the provider configuration or in the %s environment variable.", key, envKey),
+		)
+	}
+
+	return def
+}
+
+// knownStringList returns the elements of a list of strings, or nil if the list or any of its
+// elements is unknown (it depends on another resource)
+func knownStringList(ctx context.Context, d *diag.Diagnostics, list basetypes.ListValue) []string {
+	if list.IsNull() || list.IsUnknown() {
+		return nil
+	}
+	var elements []basetypes.StringValue
+	d.Append(list.ElementsAs(ctx, &elements, false)...)
+	vals := make([]string, 0, len(elements))
+	for _, e := range elements {
+		if e.IsUnknown() {
+			return nil
+		}
+		vals = append(vals, e.ValueString())
+	}
+	return vals
+}
+
 // existingOrDefaultString returns the explicit configuration value if set,
 // otherwise returns the default value.
 func existingOrDefaultString(curVal basetypes.StringValue, def string) string {
@@ -161,20 +226,19 @@ func existingOrProviderOrDefaultString(curVal basetypes.StringValue, provVal str
 	return def
 }
 
-// getPlanAndStateData unmarshals JSON strings into map structures for comparison.
-// Returns the parsed plan data and state data maps. If either JSON is invalid,
-// adds an error diagnostic and returns nil for both maps.
-func getPlanAndStateData(planDataString, stateDataString string, diag *diag.Diagnostics) (map[string]interface{}, map[string]interface{}) {
-	planData := make(map[string]interface{})
-	stateData := make(map[string]interface{})
-	if err := json.Unmarshal([]byte(planDataString), &planData); err != nil {
+// getPlanAndStateData unmarshals JSON strings for comparison. They are usually objects,
+// but can be arrays or values. Returns the parsed plan data and state data. If either
+// JSON is invalid, adds an error diagnostic and returns nil for both.
+func getPlanAndStateData(planDataString, stateDataString string, diag *diag.Diagnostics) (interface{}, interface{}) {
+	var planData, stateData interface{}
+	if err := apiclient.DecodeJSON([]byte(planDataString), &planData); err != nil {
 		diag.AddError(
 			"Error Parsing Plan Data",
 			fmt.Sprintf("Could not parse plan data JSON: %s", err.Error()),
 		)
 		return nil, nil
 	}
-	if err := json.Unmarshal([]byte(stateDataString), &stateData); err != nil {
+	if err := apiclient.DecodeJSON([]byte(stateDataString), &stateData); err != nil {
 		diag.AddError(
 			"Error Parsing Server Data",
 			fmt.Sprintf("Could not parse server data JSON: %s", err.Error()),
@@ -184,12 +248,45 @@ func getPlanAndStateData(planDataString, stateDataString string, diag *diag.Diag
 	return planData, stateData
 }
 
+// updatePriorData returns the prior data update strategies compare the planned data with: the
+// data in state, restricted to the fields configured at the last apply or now. Read replaces
+// the data in state with what the API returns, so without this, fields the API adds of its
+// own would be sent as removed. The data configured at the last apply is not known for
+// objects that were imported, in which case only the fields configured now are compared.
+func updatePriorData(stateData string, configuredData string, plannedData string) (string, error) {
+	var prior, configured, planned interface{}
+	if err := apiclient.DecodeJSON([]byte(stateData), &prior); err != nil {
+		return "", err
+	}
+	if configuredData != "" {
+		if err := apiclient.DecodeJSON([]byte(configuredData), &configured); err != nil {
+			return "", err
+		}
+	}
+	if err := apiclient.DecodeJSON([]byte(plannedData), &planned); err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(apiclient.RestrictToKeys(prior, configured, planned))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
 // getNestedValue retrieves a value from a nested map structure using dot notation.
 // For example, "metadata.timestamp" accesses data["metadata"]["timestamp"].
 // Returns an error if the path doesn't exist or traverses through a non-map value.
-func getNestedValue(data map[string]interface{}, path string) (interface{}, error) {
+// A path starting with a slash is a JSON Pointer instead, such as "/tags/0".
+func getNestedValue(data interface{}, path string) (interface{}, error) {
+	if apiclient.IsJSONPointer(path) {
+		return apiclient.GetObjectAtPointer(data, path)
+	}
 	parts := strings.Split(path, ".")
-	current := data
+	current, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("path %s not found", path)
+	}
 
 	for i, part := range parts {
 		if i == len(parts)-1 {
@@ -1,20 +1,36 @@
 package provider
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
 	"testing"
 
+	"github.com/Mastercard/terraform-provider-restapi/internal/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 func TestGetNestedValue(t *testing.T) {
 	tests := map[string]struct {
-		data        map[string]interface{}
+		data        interface{}
 		path        string
 		expected    interface{}
 		expectError bool
 	}{
+		"array_document_pointer": {
+			data:        []interface{}{"10.0.0.0/8", "192.168.0.0/16"},
+			path:        "/1",
+			expected:    "192.168.0.0/16",
+			expectError: false,
+		},
+		"array_document_dot_path": {
+			data:        []interface{}{"10.0.0.0/8"},
+			path:        "0",
+			expectError: true,
+		},
 		"simple_field": {
 			data:        map[string]interface{}{"name": "test"},
 			path:        "name",
@@ -73,6 +89,22 @@ func TestGetNestedValue(t *testing.T) {
 			expected:    nil,
 			expectError: true,
 		},
+		"json_pointer": {
+			data: map[string]interface{}{
+				"metadata": map[string]interface{}{"app.kubernetes.io/name": "web"},
+			},
+			path:        "/metadata/app.kubernetes.io~1name",
+			expected:    "web",
+			expectError: false,
+		},
+		"json_pointer_array_index": {
+			data: map[string]interface{}{
+				"tags": []interface{}{"red", "blue"},
+			},
+			path:        "/tags/1",
+			expected:    "blue",
+			expectError: false,
+		},
 	}
 
 	for name, tc := range tests {
@@ -564,6 +596,80 @@ func TestExistingOrEnvOrDefaultBool(t *testing.T) {
 	}
 }
 
+func TestExistingOrEnvOrDefaultStringList(t *testing.T) {
+	tests := map[string]struct {
+		curVal      types.List
+		envKey      string
+		envValue    string
+		def         []string
+		required    bool
+		expected    []string
+		expectError bool
+	}{
+		"use_current_value": {
+			curVal:   types.ListValueMust(types.StringType, []attr.Value{types.StringValue("a"), types.StringValue("b,c")}),
+			envKey:   "TEST_LIST",
+			envValue: "env",
+			expected: []string{"a", "b,c"},
+		},
+		"use_env_value": {
+			curVal:   types.ListNull(types.StringType),
+			envKey:   "TEST_LIST",
+			envValue: "a, b ,",
+			expected: []string{"a", "b"},
+		},
+		"use_default_value": {
+			curVal:   types.ListNull(types.StringType),
+			envKey:   "TEST_LIST_UNSET",
+			def:      []string{"default"},
+			expected: []string{"default"},
+		},
+		"unknown_value": {
+			curVal:   types.ListUnknown(types.StringType),
+			envKey:   "TEST_LIST",
+			envValue: "env",
+			required: true,
+			expected: nil,
+		},
+		"unknown_element": {
+			curVal:   types.ListValueMust(types.StringType, []attr.Value{types.StringValue("a"), types.StringUnknown()}),
+			envKey:   "TEST_LIST_UNSET",
+			required: true,
+			expected: nil,
+		},
+		"required_missing": {
+			curVal:      types.ListNull(types.StringType),
+			envKey:      "TEST_LIST_UNSET",
+			required:    true,
+			expected:    nil,
+			expectError: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if tc.envValue != "" {
+				os.Setenv(tc.envKey, tc.envValue)
+				defer os.Unsetenv(tc.envKey)
+			}
+
+			d := diag.Diagnostics{}
+			result := existingOrEnvOrDefaultStringList(context.Background(), &d, "test_key", tc.curVal, tc.envKey, tc.def, tc.required)
+
+			if fmt.Sprint(result) != fmt.Sprint(tc.expected) || (result == nil) != (tc.expected == nil) {
+				t.Errorf("expected %q, got %q", tc.expected, result)
+			}
+
+			if tc.expectError && !d.HasError() {
+				t.Error("expected error but got none")
+			}
+			if !tc.expectError && d.HasError() {
+				t.Errorf("unexpected error: %v", d.Errors())
+			}
+		})
+	}
+}
+
 func TestGetPlanAndStateData(t *testing.T) {
 	tests := map[string]struct {
 		planJSON    string
@@ -713,3 +819,77 @@ func TestNormalizeNullFields(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIErrorSummary(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected string
+	}{
+		"other error": {
+			err:      errors.New("unexpected response code '404': not found"),
+			expected: "Error Reading API Object",
+		},
+		"api unavailable": {
+			err:      fmt.Errorf("%w: circuit breaker opened", apiclient.ErrAPIUnavailable),
+			expected: "API Unavailable",
+		},
+		"interrupted": {
+			err:      &apiclient.RequestInterruptedError{Method: "GET", Path: "/api/objects/1", Err: context.Canceled},
+			expected: "Operation Interrupted",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := apiErrorSummary("Error Reading API Object", tc.err); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestUpdatePriorData(t *testing.T) {
+	state := `{"id":"1","name":"foo","color":"red","size":1,"created":"2024-01-01","metadata":{"labels":"a","revision":7}}`
+	tests := map[string]struct {
+		state      string
+		configured string
+		planned    string
+		expected   string
+	}{
+		// Fields the API added are left out, fields removed from the configuration are kept
+		"configured_and_planned_fields": {
+			state:      state,
+			configured: `{"name":"foo","color":"red","metadata":{"labels":"a"}}`,
+			planned:    `{"name":"foo","metadata":{"labels":"b"}}`,
+			expected:   `{"color":"red","metadata":{"labels":"a"},"name":"foo"}`,
+		},
+		// Without the data configured at the last apply, as for imported objects
+		"planned_fields_only": {
+			state:    state,
+			planned:  `{"name":"bar","size":2}`,
+			expected: `{"name":"foo","size":1}`,
+		},
+		"array_document": {
+			state:      `["10.0.0.0/8"]`,
+			configured: `["10.0.0.0/8"]`,
+			planned:    `[]`,
+			expected:   `["10.0.0.0/8"]`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			prior, err := updatePriorData(tc.state, tc.configured, tc.planned)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if prior != tc.expected {
+				t.Errorf("Expected prior data %s, got %s", tc.expected, prior)
+			}
+		})
+	}
+
+	if _, err := updatePriorData(`{`, "", `{}`); err == nil {
+		t.Errorf("Expected an error for invalid state data")
+	}
+}
@@ -22,7 +22,18 @@ func main() {
 		Debug:   debug,
 	}
 
-	err := providerserver.Serve(context.Background(), provider.New(version), opts)
+	ctx := context.Background()
+
+	shutdownTelemetry, err := provider.SetupTelemetry(ctx, version)
+	if err != nil {
+		log.Printf("[WARN] Unable to set up OpenTelemetry export: %s", err)
+	}
+
+	err = providerserver.Serve(ctx, provider.New(version), opts)
+
+	if err := shutdownTelemetry(ctx); err != nil {
+		log.Printf("[WARN] Unable to flush OpenTelemetry data: %s", err)
+	}
 
 	if err != nil {
 		log.Fatal(err.Error())